@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"esports-fantasy-backend/config"
+	"esports-fantasy-backend/internal/services"
+	"flag"
+	"log"
+	"os"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Reconciles the wallet ledger against cached user balances. Prints the
+// report as JSON and exits non-zero when anything needs attention.
+//
+//	go run ./cmd/reconcile            # report only
+//	go run ./cmd/reconcile -backfill  # first post opening balances for pre-ledger wallets
+func main() {
+	backfill := flag.Bool("backfill", false, "post opening balances for wallets that predate the ledger before reconciling")
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+
+	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{})
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	ledgerService := services.NewLedgerService(db, cfg)
+
+	if *backfill {
+		if _, err := ledgerService.BackfillOpeningBalances(); err != nil {
+			log.Fatal("Failed to backfill opening balances:", err)
+		}
+	}
+
+	report, err := ledgerService.Reconcile()
+	if err != nil {
+		log.Fatal("Failed to reconcile ledger:", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatal("Failed to write report:", err)
+	}
+
+	if !report.Clean() {
+		log.Printf("❌ Ledger reconciliation found %d unbalanced entries, %d account mismatches and %d user discrepancies",
+			len(report.UnbalancedEntries), len(report.AccountMismatches), len(report.UserDiscrepancies))
+		os.Exit(1)
+	}
+
+	log.Printf("✅ Ledger reconciled: %d users checked", report.UsersChecked)
+}
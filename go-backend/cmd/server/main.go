@@ -65,11 +65,14 @@ func main() {
 	usernamePrefixRepo := repository.NewUsernamePrefixRepository(db)
 	gameRepo := repository.NewGameRepository(db)
 	gameScoringRuleRepo := repository.NewGameScoringRuleRepository(db)
+	scoringRulesetRepo := repository.NewScoringRulesetRepository(db)
+	placementRepo := repository.NewPlacementRepository(db)
 	achievementRepo := repository.NewAchievementRepository(db)
 	userAchievementRepo := repository.NewUserAchievementRepository(db)
 	contestTemplateRepo := repository.NewContestTemplateRepository(db)
 	playerAnalyticsRepo := repository.NewPlayerAnalyticsRepository(db)
 	seasonLeagueRepo := repository.NewSeasonLeagueRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
 
 	// Initialize core services
 	authService := services.NewAuthService(userRepo, cfg)
@@ -78,38 +81,62 @@ func main() {
 	tournamentService := services.NewTournamentService(tournamentRepo)
 	matchService := services.NewMatchService(matchRepo)
 	contestService := services.NewContestService(contestRepo)
-	fantasyTeamService := services.NewFantasyTeamService(fantasyTeamRepo, playerRepo)
+	fantasyTeamService := services.NewFantasyTeamService(db, fantasyTeamRepo, playerRepo)
 	playerService := services.NewPlayerService(playerRepo)
-	scoringService := services.NewScoringService(db, rdb)
+	scoringService := services.NewScoringService(db, rdb, gameScoringRuleRepo, scoringRulesetRepo, placementRepo)
 	leaderboardService := services.NewLeaderboardService(rdb, fantasyTeamRepo)
+	ledgerService := services.NewLedgerService(db, cfg)
 	
 	// Initialize enhanced services
 	usernameService := services.NewUsernameService(userRepo, usernamePrefixRepo, cfg)
-	gameService := services.NewGameService(gameRepo, gameScoringRuleRepo, cfg)
+	gameService := services.NewGameService(db, gameRepo, gameScoringRuleRepo, scoringRulesetRepo, placementRepo, cfg)
 	achievementService := services.NewAchievementService(achievementRepo, userAchievementRepo, userRepo, cfg)
 	contestTemplateService := services.NewContestTemplateService(contestTemplateRepo, contestRepo, gameRepo, cfg)
 	playerAnalyticsService := services.NewPlayerAnalyticsService(playerAnalyticsRepo, playerRepo, gameRepo, cfg)
-	seasonLeagueService := services.NewSeasonLeagueService(seasonLeagueRepo, gameRepo, userRepo, cfg)
-	referralService := services.NewReferralService(userRepo, cfg)
+	seasonLeagueService := services.NewSeasonLeagueService(seasonLeagueRepo, gameRepo, userRepo, ledgerService, cfg)
+	referralService := services.NewReferralService(db, userRepo, ledgerService, cfg)
+	notificationService := services.NewNotificationService(notificationRepo, rdb)
 
 	// Initialize advanced services
-	phonePeService := services.NewPhonePeService(cfg, userRepo, transactionRepo, contestRepo)
-	paymentService := services.NewPaymentService(transactionRepo, userRepo, cfg)
+	paymentGateways := services.NewPaymentGateways(cfg)
+	depositService := services.NewDepositService(db, userRepo, ledgerService, paymentGateways, cfg)
+	paymentReconciler := services.NewPaymentReconciler(cfg, db, depositService, paymentGateways)
+	contestJoinService := services.NewContestJoinService(db, fantasyTeamService, ledgerService, depositService, notificationService)
+	lineupService := services.NewLineupService(db, fantasyTeamService, contestJoinService)
+	depositService.AddListener(contestJoinService)
 	analyticsService := services.NewAnalyticsService(cfg, db, rdb, userRepo, contestRepo, transactionRepo, matchRepo)
 	matchSimulationService := services.NewMatchSimulationService(cfg, matchRepo, playerRepo, scoringService, leaderboardService, rdb)
-	autoContestService := services.NewAutoContestService(cfg, contestRepo, matchRepo, fantasyTeamRepo, transactionRepo, userRepo, leaderboardService)
+	matchFeedService := services.NewMatchFeedService(cfg, scoringService)
+	settlementService := services.NewSettlementService(db, ledgerService)
+	contestCancellationService := services.NewContestCancellationService(db, ledgerService, notificationService)
+	autoContestService := services.NewAutoContestService(cfg, contestRepo, matchRepo, fantasyTeamRepo, transactionRepo, userRepo, leaderboardService, gameService, settlementService, contestCancellationService)
+	settlementCorrectionService := services.NewSettlementCorrectionService(db, scoringService, leaderboardService, notificationService, ledgerService)
+	payoutProvider, err := services.NewPayoutProvider(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize payout provider:", err)
+	}
+	if payoutProvider == nil {
+		log.Println("⚠️ PAYOUT_PROVIDER is not set, withdrawals are disabled")
+	}
+	withdrawalService := services.NewWithdrawalService(db, ledgerService, notificationService, payoutProvider, cfg)
 
 	// Initialize handlers
 	authHandler := httphandlers.NewAuthHandler(authService, userService)
 	firebaseAuthHandler := httphandlers.NewFirebaseAuthHandler(firebaseAuthService)
 	userHandler := httphandlers.NewUserHandler(userService)
-	adminHandler := httphandlers.NewAdminHandler(tournamentService, matchService, contestService, playerService, scoringService)
-	contestHandler := httphandlers.NewContestHandler(contestService, fantasyTeamService, leaderboardService)
-	paymentHandler := httphandlers.NewPaymentHandler(paymentService)
-	phonePeHandler := httphandlers.NewPhonePeHandler(phonePeService)
+	adminHandler := httphandlers.NewAdminHandler(tournamentService, matchService, contestService, playerService, scoringService, contestCancellationService)
+	contestHandler := httphandlers.NewContestHandler(contestService, contestJoinService, fantasyTeamService, leaderboardService, scoringService)
+	lineupHandler := httphandlers.NewLineupHandler(lineupService)
+	paymentHandler := httphandlers.NewPaymentHandler(depositService, paymentReconciler)
+	phonePeHandler := httphandlers.NewPhonePeHandler(depositService)
 	analyticsHandler := httphandlers.NewAnalyticsHandler(analyticsService)
 	matchSimulationHandler := httphandlers.NewMatchSimulationHandler(matchSimulationService)
 	autoContestHandler := httphandlers.NewAutoContestHandler(autoContestService)
+	matchFeedHandler := httphandlers.NewMatchFeedHandler(matchFeedService)
+	settlementCorrectionHandler := httphandlers.NewSettlementCorrectionHandler(settlementCorrectionService)
+	notificationHandler := httphandlers.NewNotificationHandler(notificationService)
+	ledgerHandler := httphandlers.NewLedgerHandler(ledgerService)
+	withdrawalHandler := httphandlers.NewWithdrawalHandler(withdrawalService)
 	
 	// Initialize enhanced handlers
 	adminEnhancedHandler := httphandlers.NewAdminEnhancedHandler(usernameService, gameService)
@@ -128,6 +155,11 @@ func main() {
 	if err := autoContestService.StartScheduler(); err != nil {
 		log.Printf("❌ Failed to start auto contest service: %v", err)
 	}
+
+	// Start payment reconciliation
+	if err := paymentReconciler.StartScheduler(); err != nil {
+		log.Printf("❌ Failed to start payment reconciliation: %v", err)
+	}
 	
 	// Start analytics caching
 	analyticsService.StartAnalyticsCaching()
@@ -147,7 +179,7 @@ func main() {
 	})
 
 	// Setup routes
-	routes.SetupRoutes(router, authHandler, firebaseAuthHandler, userHandler, adminHandler, contestHandler, paymentHandler, phonePeHandler, analyticsHandler, matchSimulationHandler, autoContestHandler, wsHandler, adminEnhancedHandler, userEnhancedHandler, adminAdvancedHandler, userAdvancedHandler, cfg)
+	routes.SetupRoutes(router, authHandler, firebaseAuthHandler, userHandler, adminHandler, contestHandler, lineupHandler, paymentHandler, phonePeHandler, analyticsHandler, matchSimulationHandler, autoContestHandler, matchFeedHandler, settlementCorrectionHandler, notificationHandler, ledgerHandler, withdrawalHandler, wsHandler, adminEnhancedHandler, userEnhancedHandler, adminAdvancedHandler, userAdvancedHandler, cfg)
 
 	// Server configuration
 	srv := &http.Server{
@@ -178,6 +210,7 @@ func main() {
 
 	// Stop auto contest service
 	autoContestService.StopScheduler()
+	paymentReconciler.StopScheduler()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -232,12 +265,33 @@ func autoMigrate(db *gorm.DB) error {
 		&models.Contest{},
 		&models.FantasyTeam{},
 		&models.FantasyTeamPlayer{},
+		&models.FantasyTeamEdit{},
+		&models.SavedLineup{},
+		&models.SavedLineupPlayer{},
+		&models.ContestEntry{},
+		&models.PendingContestJoin{},
 		&models.PlayerMatchStats{},
+		&models.MatchStatEvent{},
 		&models.Transaction{},
+		&models.GatewayCallback{},
+		&models.PaymentReconciliationReport{},
+		&models.Notification{},
+		&models.LedgerAccount{},
+		&models.JournalEntry{},
+		&models.JournalLine{},
+		&models.ContestSettlement{},
+		&models.SettlementPayout{},
+		&models.SettlementCorrection{},
+		&models.SettlementCorrectionEntry{},
+		&models.Withdrawal{},
 		// New enhanced models
 		&models.UsernamePrefix{},
 		&models.Game{},
 		&models.GameScoringRule{},
+		&models.ScoringRuleset{},
+		&models.GamePlacementPoint{},
+		&models.MatchTeam{},
+		&models.MatchTeamPlacement{},
 		&models.Achievement{},
 		&models.UserAchievement{},
 		&models.ContestTemplate{},
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -57,6 +58,25 @@ type Config struct {
 	MatchSimulationEnabled bool
 	LiveScoringEnabled   bool
 	
+	// Match Data Feeds
+	MatchFeedDir         string
+	MatchFeedReplaySpeed float64
+	
+	// Wallet Buckets
+	EntryFeeBucketOrder     []string // Buckets that pay an entry fee, first to last
+	EntryFeeMaxBonusPercent float64  // Share of an entry fee the bonus bucket may pay
+	
+	// Withdrawals
+	WithdrawalMinAmount  float64 // Smallest amount a user may withdraw
+	WithdrawalDailyLimit float64 // Most a user may withdraw per calendar day
+	WithdrawalDailyCount int     // Most withdrawal requests a user may make per day
+	PayoutProvider       string  // Payout provider for withdrawals; unset disables them, and fake is only allowed with DUMMY
+	
+	// Payment Reconciliation
+	PaymentReconciliationEnabled bool
+	DepositPendingMinutes        int // Age at which a pending deposit is checked with its gateway
+	DepositExpiryHours           int // Age at which a deposit still pending is failed
+	
 	// Legacy Razorpay (for backward compatibility)
 	RazorpayKeyID       string
 	RazorpaySecret      string
@@ -78,6 +98,13 @@ func LoadConfig() *Config {
 	phonePeSaltIndex, _ := strconv.Atoi(getEnv("PHONEPE_SALT_INDEX", "1"))
 	contestLockMinutes, _ := strconv.Atoi(getEnv("CONTEST_LOCK_MINUTES_BEFORE_MATCH", "15"))
 	analyticsRetentionDays, _ := strconv.Atoi(getEnv("ANALYTICS_RETENTION_DAYS", "365"))
+	matchFeedReplaySpeed, _ := strconv.ParseFloat(getEnv("MATCH_FEED_REPLAY_SPEED", "1"), 64)
+	entryFeeMaxBonusPercent, _ := strconv.ParseFloat(getEnv("ENTRY_FEE_MAX_BONUS_PERCENT", "10"), 64)
+	withdrawalMinAmount, _ := strconv.ParseFloat(getEnv("WITHDRAWAL_MIN_AMOUNT", "100"), 64)
+	withdrawalDailyLimit, _ := strconv.ParseFloat(getEnv("WITHDRAWAL_DAILY_LIMIT", "50000"), 64)
+	withdrawalDailyCount, _ := strconv.Atoi(getEnv("WITHDRAWAL_DAILY_COUNT", "3"))
+	depositPendingMinutes, _ := strconv.Atoi(getEnv("DEPOSIT_PENDING_MINUTES", "15"))
+	depositExpiryHours, _ := strconv.Atoi(getEnv("DEPOSIT_EXPIRY_HOURS", "24"))
 
 	return &Config{
 		// Database Configuration
@@ -128,6 +155,25 @@ func LoadConfig() *Config {
 		MatchSimulationEnabled: getEnv("MATCH_SIMULATION_ENABLED", "true") == "true",
 		LiveScoringEnabled:     getEnv("LIVE_SCORING_ENABLED", "true") == "true",
 		
+		// Match Data Feeds
+		MatchFeedDir:         getEnv("MATCH_FEED_DIR", "./feeds"),
+		MatchFeedReplaySpeed: matchFeedReplaySpeed,
+		
+		// Wallet Buckets
+		EntryFeeBucketOrder:     strings.Split(getEnv("ENTRY_FEE_BUCKET_ORDER", "bonus,deposit,winnings"), ","),
+		EntryFeeMaxBonusPercent: entryFeeMaxBonusPercent,
+		
+		// Withdrawals
+		WithdrawalMinAmount:  withdrawalMinAmount,
+		WithdrawalDailyLimit: withdrawalDailyLimit,
+		WithdrawalDailyCount: withdrawalDailyCount,
+		PayoutProvider:       getEnv("PAYOUT_PROVIDER", ""),
+		
+		// Payment Reconciliation
+		PaymentReconciliationEnabled: getEnv("PAYMENT_RECONCILIATION_ENABLED", "true") == "true",
+		DepositPendingMinutes:        depositPendingMinutes,
+		DepositExpiryHours:           depositExpiryHours,
+		
 		// Legacy Razorpay (for backward compatibility)
 		RazorpayKeyID:  getEnv("RAZORPAY_KEY_ID", ""),
 		RazorpaySecret: getEnv("RAZORPAY_SECRET", ""),
@@ -439,4 +439,143 @@ func (h *AdminEnhancedHandler) DeleteScoringRule(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Scoring rule deleted successfully"})
-}
\ No newline at end of file
+}
+// === PLACEMENT POINTS ===
+
+// GetPlacementPoints godoc
+// @Summary Get placement points table
+// @Description Admin gets the squad placement points table for a game
+// @Tags admin-enhanced
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Game ID"
+// @Success 200 {array} models.GamePlacementPoint
+// @Router /admin/games/{id}/placement-points [get]
+func (h *AdminEnhancedHandler) GetPlacementPoints(c *gin.Context) {
+	gameID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid game ID"})
+		return
+	}
+
+	points, err := h.gameService.GetPlacementPoints(gameID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get placement points"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"placement_points": points,
+		"count":            len(points),
+	})
+}
+
+// SetPlacementPoints godoc
+// @Summary Replace placement points table
+// @Description Admin replaces the squad placement points table for a game. Placements not listed earn no points.
+// @Tags admin-enhanced
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Game ID"
+// @Param table body models.SetPlacementPointsRequest true "Placement points table"
+// @Success 200 {array} models.GamePlacementPoint
+// @Router /admin/games/{id}/placement-points [put]
+func (h *AdminEnhancedHandler) SetPlacementPoints(c *gin.Context) {
+	gameID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid game ID"})
+		return
+	}
+
+	var req models.SetPlacementPointsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	points, err := h.gameService.SetPlacementPoints(gameID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"placement_points": points,
+		"count":            len(points),
+	})
+}
+
+// === VERSIONED SCORING RULESETS ===
+
+// GetScoringRulesets godoc
+// @Summary Get scoring ruleset versions
+// @Description Admin lists all published scoring ruleset versions for a game
+// @Tags admin-enhanced
+// @Produce json
+// @Security BearerAuth
+// @Param game_id query string true "Game ID"
+// @Success 200 {array} models.ScoringRuleset
+// @Router /admin/scoring-rulesets [get]
+func (h *AdminEnhancedHandler) GetScoringRulesets(c *gin.Context) {
+	gameID, err := uuid.Parse(c.Query("game_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid game ID"})
+		return
+	}
+
+	rulesets, err := h.gameService.GetScoringRulesets(gameID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scoring rulesets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rulesets": rulesets,
+		"count":    len(rulesets),
+	})
+}
+
+// DiffScoringRulesets godoc
+// @Summary Diff two scoring ruleset versions
+// @Description Admin compares two scoring ruleset versions of a game
+// @Tags admin-enhanced
+// @Produce json
+// @Security BearerAuth
+// @Param game_id query string true "Game ID"
+// @Param from query int true "From version"
+// @Param to query int true "To version"
+// @Success 200 {array} models.ScoringRulesetDiffEntry
+// @Router /admin/scoring-rulesets/diff [get]
+func (h *AdminEnhancedHandler) DiffScoringRulesets(c *gin.Context) {
+	gameID, err := uuid.Parse(c.Query("game_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid game ID"})
+		return
+	}
+
+	fromVersion, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from version"})
+		return
+	}
+
+	toVersion, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to version"})
+		return
+	}
+
+	diff, err := h.gameService.DiffScoringRulesets(gameID, fromVersion, toVersion)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":    fromVersion,
+		"to":      toVersion,
+		"changes": diff,
+		"count":   len(diff),
+	})
+}
@@ -1,20 +1,25 @@
 package http
 
 import (
+	"errors"
 	"esports-fantasy-backend/internal/models"
 	"esports-fantasy-backend/internal/services"
+	"io"
 	"net/http"
+	"path/filepath"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type AdminHandler struct {
-	tournamentService services.TournamentService
-	matchService      services.MatchService
-	contestService    services.ContestService
-	playerService     services.PlayerService
-	scoringService    services.ScoringService
+	tournamentService   services.TournamentService
+	matchService        services.MatchService
+	contestService      services.ContestService
+	playerService       services.PlayerService
+	scoringService      services.ScoringService
+	cancellationService services.ContestCancellationService
 }
 
 func NewAdminHandler(
@@ -23,13 +28,15 @@ func NewAdminHandler(
 	contestService services.ContestService,
 	playerService services.PlayerService,
 	scoringService services.ScoringService,
+	cancellationService services.ContestCancellationService,
 ) *AdminHandler {
 	return &AdminHandler{
-		tournamentService: tournamentService,
-		matchService:      matchService,
-		contestService:    contestService,
-		playerService:     playerService,
-		scoringService:    scoringService,
+		tournamentService:   tournamentService,
+		matchService:        matchService,
+		contestService:      contestService,
+		playerService:       playerService,
+		scoringService:      scoringService,
+		cancellationService: cancellationService,
 	}
 }
 
@@ -151,6 +158,10 @@ func (h *AdminHandler) UpdateMatchStatus(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Status is required"})
 		return
 	}
+	if strings.EqualFold(status, services.MatchStatusCancelled) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Use the cancel endpoint so entry fees are refunded"})
+		return
+	}
 
 	if err := h.matchService.UpdateMatchStatus(matchID, status); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update match status"})
@@ -160,6 +171,80 @@ func (h *AdminHandler) UpdateMatchStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Match status updated successfully"})
 }
 
+// CancelMatch godoc
+// @Summary Cancel a match
+// @Description Admin cancels a match and all its contests, refunding every entry fee to the wallet bucket that paid it. Safe to retry.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param cancel body models.CancelContestRequest true "Cancellation reason"
+// @Success 200 {object} services.MatchCancellationResult
+// @Router /admin/matches/{id}/cancel [post]
+func (h *AdminHandler) CancelMatch(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	var req models.CancelContestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cancellation reason is required"})
+		return
+	}
+
+	result, err := h.cancellationService.CancelMatch(matchID, req.Reason)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrMatchNotCancellable) || errors.Is(err, services.ErrContestNotCancellable) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CancelContest godoc
+// @Summary Cancel a contest
+// @Description Admin cancels a contest, refunding every entry fee to the wallet bucket that paid it. Safe to retry.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Contest ID"
+// @Param cancel body models.CancelContestRequest true "Cancellation reason"
+// @Success 200 {object} services.ContestRefundResult
+// @Router /admin/contests/{id}/cancel [post]
+func (h *AdminHandler) CancelContest(c *gin.Context) {
+	contestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contest ID"})
+		return
+	}
+
+	var req models.CancelContestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cancellation reason is required"})
+		return
+	}
+
+	result, err := h.cancellationService.CancelContest(contestID, req.Reason)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrContestNotCancellable) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // CreateContest godoc
 // @Summary Create a new contest
 // @Description Admin endpoint to create a new contest
@@ -179,6 +264,11 @@ func (h *AdminHandler) CreateContest(c *gin.Context) {
 
 	contest.ID = uuid.New()
 	if err := h.contestService.CreateContest(&contest); err != nil {
+		if errors.Is(err, services.ErrInvalidPrizeStructure) || errors.Is(err, services.ErrInvalidMinEntries) ||
+			errors.Is(err, services.ErrInvalidMaxEntriesPerUser) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contest"})
 		return
 	}
@@ -254,6 +344,354 @@ func (h *AdminHandler) UpdatePlayerStats(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Player stats updated successfully"})
 }
 
+// ImportPlayerStats godoc
+// @Summary Bulk import player statistics for a match
+// @Description Admin uploads a whole match's stats as CSV or a JSON array, either as a multipart "file" or as the raw request body. Every row is validated first; with dry_run=true only the row-level errors are returned, otherwise all rows are written in one transaction.
+// @Tags admin
+// @Accept text/csv,json,mpfd
+// @Produce json
+// @Security BearerAuth
+// @Param matchId path string true "Match ID"
+// @Param dry_run query bool false "Validate without writing"
+// @Param file formData file false "CSV or JSON stats sheet"
+// @Success 200 {object} models.StatsImportResult
+// @Failure 422 {object} models.StatsImportResult
+// @Router /admin/stats/match/{matchId}/import [post]
+func (h *AdminHandler) ImportPlayerStats(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("matchId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+
+	var body io.Reader = c.Request.Body
+	isCSV := strings.Contains(c.ContentType(), "csv")
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing stats file"})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read stats file"})
+			return
+		}
+		defer file.Close()
+
+		body = file
+		isCSV = strings.ToLower(filepath.Ext(fileHeader.Filename)) == ".csv"
+	}
+
+	var rows []models.PlayerStatsImportRow
+	if isCSV {
+		rows, err = services.ParseStatsImportCSV(body)
+	} else {
+		rows, err = services.ParseStatsImportJSON(body)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(rows) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stats sheet has no rows"})
+		return
+	}
+
+	result, err := h.scoringService.ImportPlayerStats(matchID, rows, dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(result.Errors) > 0 && !dryRun {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RecordMatchEvent godoc
+// @Summary Record a live match stat event
+// @Description Admin or feed appends a single stat event (kill, knockout, revive, etc.) to the match event log
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param event body models.LiveMatchUpdateRequest true "Stat event"
+// @Success 201 {object} models.MatchStatEvent
+// @Router /admin/matches/{id}/events [post]
+func (h *AdminHandler) RecordMatchEvent(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	var req models.LiveMatchUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	event, err := h.scoringService.RecordMatchEvent(matchID, &req, services.EventSourceAdmin)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, event)
+}
+
+// GetMatchEvents godoc
+// @Summary Get match stat events
+// @Description Get the stat event log of a match, optionally for a single player
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param player_id query string false "Player ID"
+// @Success 200 {array} models.MatchStatEvent
+// @Router /admin/matches/{id}/events [get]
+func (h *AdminHandler) GetMatchEvents(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	var playerID *uuid.UUID
+	if playerIDStr := c.Query("player_id"); playerIDStr != "" {
+		id, err := uuid.Parse(playerIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+			return
+		}
+		playerID = &id
+	}
+
+	events, err := h.scoringService.GetMatchEvents(matchID, playerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get match events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"count":  len(events),
+	})
+}
+
+// CorrectMatchEvent godoc
+// @Summary Correct a match stat event
+// @Description Admin reverses a stat event by appending a compensating event
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param eventId path string true "Event ID"
+// @Param correction body models.CorrectMatchEventRequest true "Correction reason"
+// @Success 201 {object} models.MatchStatEvent
+// @Router /admin/match-events/{eventId}/correct [post]
+func (h *AdminHandler) CorrectMatchEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("eventId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var req models.CorrectMatchEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	correction, err := h.scoringService.CorrectMatchEvent(eventID, req.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, correction)
+}
+
+// RebuildPlayerStats godoc
+// @Summary Rebuild player match statistics
+// @Description Admin recomputes a player's stats for a match from the event log
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param matchId path string true "Match ID"
+// @Param playerId path string true "Player ID"
+// @Success 200 {object} models.PlayerMatchStats
+// @Router /admin/stats/match/{matchId}/player/{playerId}/rebuild [post]
+func (h *AdminHandler) RebuildPlayerStats(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("matchId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	playerID, err := uuid.Parse(c.Param("playerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	stats, err := h.scoringService.RebuildPlayerStats(matchID, playerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rebuild player stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// SetMatchPlacements godoc
+// @Summary Record squad placements for a match
+// @Description Admin records where each squad finished; placement points are added to every player of the squad
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param results body models.SetMatchPlacementsRequest true "Squad placements"
+// @Success 200 {array} models.MatchTeamPlacement
+// @Router /admin/matches/{id}/placements [put]
+func (h *AdminHandler) SetMatchPlacements(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	var req models.SetMatchPlacementsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	placements, err := h.scoringService.SetMatchPlacements(matchID, req.Results)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, placements)
+}
+
+// SetMatchTeams godoc
+// @Summary Set the squads playing in a match
+// @Description Admin lists the eSports teams in a match; fantasy teams for the match may then only pick their players
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Param teams body models.SetMatchTeamsRequest true "Squads in the match"
+// @Success 200 {array} models.MatchTeam
+// @Router /admin/matches/{id}/teams [put]
+func (h *AdminHandler) SetMatchTeams(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	var req models.SetMatchTeamsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	teams, err := h.matchService.SetMatchTeams(matchID, req.ESportsTeamIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, teams)
+}
+
+// GetMatchTeams godoc
+// @Summary Get the squads playing in a match
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Success 200 {array} models.MatchTeam
+// @Router /admin/matches/{id}/teams [get]
+func (h *AdminHandler) GetMatchTeams(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	teams, err := h.matchService.GetMatchTeams(matchID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get match teams"})
+		return
+	}
+
+	c.JSON(http.StatusOK, teams)
+}
+
+// RecalculateMatchScores godoc
+// @Summary Recompute fantasy team scores for a match
+// @Description Admin rebuilds every fantasy team total and leaderboard for a match from scratch, repairing totals that have drifted from the incremental updates
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Match ID"
+// @Success 200 {object} map[string]string
+// @Router /admin/matches/{id}/rescore [post]
+func (h *AdminHandler) RecalculateMatchScores(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	if err := h.scoringService.RecalculateFantasyTeamScores(matchID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to recalculate fantasy team scores"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Fantasy team scores recalculated successfully"})
+}
+
+// PreviewScoring godoc
+// @Summary Preview a candidate scoring ruleset
+// @Description Admin rescores a completed match with candidate rules and sees the effect on player points, fantasy team totals and contest leaderboards. Nothing is saved.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param preview body models.ScoringPreviewRequest true "Match and candidate rules"
+// @Success 200 {object} models.ScoringPreviewResult
+// @Router /admin/scoring-rulesets/preview [post]
+func (h *AdminHandler) PreviewScoring(c *gin.Context) {
+	var req models.ScoringPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	preview, err := h.scoringService.PreviewScoring(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
 // CreateESportsTeam godoc
 // @Summary Create a new eSports team
 // @Description Admin endpoint to create a new eSports team
@@ -1,6 +1,7 @@
 package http
 
 import (
+        "errors"
         "net/http"
 
         "esports-fantasy-backend/internal/services"
@@ -61,7 +62,11 @@ func (h *AutoContestHandler) ForceDistributePrizes(c *gin.Context) {
         }
 
         if err := h.autoContestService.ForceDistributePrizes(contestID); err != nil {
-                c.JSON(http.StatusInternalServerError, gin.H{
+                status := http.StatusInternalServerError
+                if errors.Is(err, services.ErrContestNotSettleable) {
+                        status = http.StatusConflict
+                }
+                c.JSON(status, gin.H{
                         "success": false,
                         "message": "Failed to distribute prizes",
                         "error":   err.Error(),
@@ -101,7 +106,11 @@ func (h *AutoContestHandler) ForceLockContest(c *gin.Context) {
         }
 
         if err := h.autoContestService.ForceLockContest(contestID); err != nil {
-                c.JSON(http.StatusInternalServerError, gin.H{
+                status := http.StatusInternalServerError
+                if errors.Is(err, services.ErrContestNotOpen) {
+                        status = http.StatusConflict
+                }
+                c.JSON(status, gin.H{
                         "success": false,
                         "message": "Failed to lock contest",
                         "error":   err.Error(),
@@ -117,4 +126,31 @@ func (h *AutoContestHandler) ForceLockContest(c *gin.Context) {
                         "action":     "FORCE_CONTEST_LOCK",
                 },
         })
-}
\ No newline at end of file
+}
+// GetContestSettlement returns the settlement record and payouts of a contest
+func (h *AutoContestHandler) GetContestSettlement(c *gin.Context) {
+        contestID := c.Param("contestId")
+        if contestID == "" {
+                c.JSON(http.StatusBadRequest, gin.H{
+                        "success": false,
+                        "message": "Contest ID is required",
+                })
+                return
+        }
+
+        settlement, err := h.autoContestService.GetContestSettlement(contestID)
+        if err != nil {
+                c.JSON(http.StatusNotFound, gin.H{
+                        "success": false,
+                        "message": "Settlement not found",
+                        "error":   err.Error(),
+                })
+                return
+        }
+
+        c.JSON(http.StatusOK, gin.H{
+                "success": true,
+                "message": "Settlement retrieved successfully",
+                "data":    settlement,
+        })
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"esports-fantasy-backend/internal/models"
 	"esports-fantasy-backend/internal/services"
 	"net/http"
@@ -12,19 +13,25 @@ import (
 
 type ContestHandler struct {
 	contestService      services.ContestService
+	contestJoinService  services.ContestJoinService
 	fantasyTeamService  services.FantasyTeamService
 	leaderboardService  services.LeaderboardService
+	scoringService      services.ScoringService
 }
 
 func NewContestHandler(
 	contestService services.ContestService,
+	contestJoinService services.ContestJoinService,
 	fantasyTeamService services.FantasyTeamService,
 	leaderboardService services.LeaderboardService,
+	scoringService services.ScoringService,
 ) *ContestHandler {
 	return &ContestHandler{
 		contestService:     contestService,
+		contestJoinService: contestJoinService,
 		fantasyTeamService: fantasyTeamService,
 		leaderboardService: leaderboardService,
+		scoringService:     scoringService,
 	}
 }
 
@@ -79,14 +86,14 @@ func (h *ContestHandler) GetContestDetails(c *gin.Context) {
 }
 
 // CreateFantasyTeam godoc
-// @Summary Create a fantasy team
-// @Description Create a new fantasy team for a contest
+// @Summary Join a contest with a fantasy team
+// @Description Create a fantasy team in a contest, paying the entry fee from the wallet
 // @Tags fantasy
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param team body models.CreateTeamRequest true "Fantasy team data"
-// @Success 201 {object} models.FantasyTeam
+// @Success 201 {object} models.ContestEntry
 // @Router /fantasy/teams [post]
 func (h *ContestHandler) CreateFantasyTeam(c *gin.Context) {
 	user, exists := c.Get("user")
@@ -103,13 +110,73 @@ func (h *ContestHandler) CreateFantasyTeam(c *gin.Context) {
 		return
 	}
 
-	team, err := h.fantasyTeamService.CreateFantasyTeam(userModel.ID, &req)
+	entry, err := h.contestJoinService.JoinContest(userModel.ID, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(joinErrorStatus(err), teamErrorBody(err))
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"team": team})
+	c.JSON(http.StatusCreated, gin.H{"team": entry.FantasyTeam, "entry": entry})
+}
+
+// PayAndJoinContest godoc
+// @Summary Join a contest, paying any shortfall
+// @Description Join a contest, or if the wallet cannot cover the entry fee, start a deposit for the difference; the team joins once the deposit completes
+// @Tags fantasy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param team body models.PayAndJoinRequest true "Fantasy team data and gateway"
+// @Success 200 {object} services.PayAndJoinResult
+// @Router /fantasy/teams/pay-and-join [post]
+func (h *ContestHandler) PayAndJoinContest(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	var req models.PayAndJoinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	result, err := h.contestJoinService.PayAndJoin(userModel.ID, &req)
+	if err != nil {
+		c.JSON(joinErrorStatus(err), teamErrorBody(err))
+		return
+	}
+
+	if result.Entry != nil {
+		c.JSON(http.StatusCreated, result)
+		return
+	}
+	c.JSON(http.StatusAccepted, result)
+}
+
+func joinErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrContestFull), errors.Is(err, services.ErrContestNotOpen), errors.Is(err, services.ErrEntryLimitReached),
+		errors.Is(err, services.ErrDuplicateLineup):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInsufficientFunds):
+		return http.StatusPaymentRequired
+	}
+	return http.StatusBadRequest
+}
+
+// teamErrorBody adds the broken team rules, when there are any, so the app
+// can point at the fields to fix
+func teamErrorBody(err error) gin.H {
+	body := gin.H{"error": err.Error()}
+	var invalid *services.TeamValidationError
+	if errors.As(err, &invalid) {
+		body["violations"] = invalid.Violations
+	}
+	return body
 }
 
 // GetUserTeams godoc
@@ -168,4 +235,180 @@ func (h *ContestHandler) GetLeaderboard(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{"leaderboard": leaderboard})
-}
\ No newline at end of file
+}
+
+// UpdateFantasyTeam godoc
+// @Summary Edit a fantasy team
+// @Description Swap players, captain or vice-captain while the contest is open. Rejected once the contest locks.
+// @Tags fantasy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Fantasy team ID"
+// @Param team body models.UpdateTeamRequest true "New lineup"
+// @Success 200 {object} models.FantasyTeam
+// @Router /fantasy/teams/{id} [put]
+func (h *ContestHandler) UpdateFantasyTeam(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req models.UpdateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	team, err := h.fantasyTeamService.UpdateFantasyTeam(userModel.ID, teamID, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, services.ErrTeamNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrTeamLocked), errors.Is(err, services.ErrDuplicateLineup):
+			status = http.StatusConflict
+		}
+		c.JSON(status, teamErrorBody(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"team": team})
+}
+
+// GetTeamHistory godoc
+// @Summary Get fantasy team edit history
+// @Description List every lineup change made to the user's team, oldest first
+// @Tags fantasy
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Fantasy team ID"
+// @Success 200 {array} models.FantasyTeamEdit
+// @Router /fantasy/teams/{id}/history [get]
+func (h *ContestHandler) GetTeamHistory(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	edits, err := h.fantasyTeamService.GetTeamHistory(userModel.ID, teamID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrTeamNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"history": edits})
+}
+
+// GetTeamPointsBreakdown godoc
+// @Summary Get fantasy team points breakdown
+// @Description Get the itemised points of every player in a fantasy team, including captain and vice-captain multipliers
+// @Tags fantasy
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Fantasy team ID"
+// @Success 200 {object} models.FantasyTeamPointsBreakdown
+// @Router /fantasy/teams/{id}/points [get]
+func (h *ContestHandler) GetTeamPointsBreakdown(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	breakdown, err := h.scoringService.GetFantasyTeamPointsBreakdown(teamID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Failed to get team points breakdown"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"breakdown": breakdown})
+}
+
+// GetPlayerPointsBreakdown godoc
+// @Summary Get player points breakdown
+// @Description Get the itemised fantasy points a player earned in a match. With contest_id the points use the contest's pinned ruleset, matching its team breakdowns; otherwise the match's current rules.
+// @Tags contests
+// @Produce json
+// @Param matchId path string true "Match ID"
+// @Param playerId path string true "Player ID"
+// @Param contest_id query string false "Contest ID"
+// @Success 200 {object} models.PlayerPointsBreakdown
+// @Router /matches/{matchId}/players/{playerId}/points [get]
+func (h *ContestHandler) GetPlayerPointsBreakdown(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("matchId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	playerID, err := uuid.Parse(c.Param("playerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	var contestID *uuid.UUID
+	if param := c.Query("contest_id"); param != "" {
+		id, err := uuid.Parse(param)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contest ID"})
+			return
+		}
+		contestID = &id
+	}
+
+	breakdown, err := h.scoringService.GetPlayerPointsBreakdown(matchID, playerID, contestID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Failed to get player points breakdown"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"breakdown": breakdown})
+}
+
+// GetMatchPlacements godoc
+// @Summary Get squad placements for a match
+// @Description Get where each squad finished in a match, best placement first
+// @Tags contests
+// @Produce json
+// @Param matchId path string true "Match ID"
+// @Success 200 {array} models.MatchTeamPlacement
+// @Router /matches/{matchId}/placements [get]
+func (h *ContestHandler) GetMatchPlacements(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("matchId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	placements, err := h.scoringService.GetMatchPlacements(matchID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get match placements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"placements": placements})
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"net/http"
+
+	"esports-fantasy-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type LedgerHandler struct {
+	ledgerService services.LedgerService
+}
+
+func NewLedgerHandler(ledgerService services.LedgerService) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerService: ledgerService,
+	}
+}
+
+// Reconcile godoc
+// @Summary Reconcile the wallet ledger
+// @Description Check journal entries balance and flag users whose cached wallet balance differs from the ledger
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} services.LedgerReconciliation
+// @Router /admin/ledger/reconcile [get]
+func (h *LedgerHandler) Reconcile(c *gin.Context) {
+	report, err := h.ledgerService.Reconcile()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to reconcile ledger",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Ledger reconciled",
+		"data": gin.H{
+			"clean":  report.Clean(),
+			"report": report,
+		},
+	})
+}
+
+// GetUserBalance godoc
+// @Summary Get a user's ledger balance
+// @Description Compare a user's cached wallet balance with their cash and bonus ledger accounts
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} services.UserLedgerBalance
+// @Router /admin/ledger/users/{id} [get]
+func (h *LedgerHandler) GetUserBalance(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid user ID",
+		})
+		return
+	}
+
+	balance, err := h.ledgerService.GetUserBalance(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "User not found",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Ledger balance retrieved successfully",
+		"data":    balance,
+	})
+}
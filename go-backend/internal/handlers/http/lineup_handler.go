@@ -0,0 +1,259 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"esports-fantasy-backend/internal/models"
+	"esports-fantasy-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type LineupHandler struct {
+	lineupService services.LineupService
+}
+
+func NewLineupHandler(lineupService services.LineupService) *LineupHandler {
+	return &LineupHandler{
+		lineupService: lineupService,
+	}
+}
+
+// lineupErrorStatus maps lineup errors to a status; anything else is a bad request
+func lineupErrorStatus(err error) int {
+	if errors.Is(err, services.ErrLineupNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+// CreateLineup godoc
+// @Summary Save a lineup for a match
+// @Description Save players, captain and vice-captain for a match, to enter into any of its contests
+// @Tags fantasy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param lineup body models.SaveLineupRequest true "Lineup"
+// @Success 201 {object} models.SavedLineup
+// @Router /fantasy/lineups [post]
+func (h *LineupHandler) CreateLineup(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	var req models.SaveLineupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	lineup, err := h.lineupService.CreateLineup(userModel.ID, &req)
+	if err != nil {
+		c.JSON(lineupErrorStatus(err), teamErrorBody(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"lineup": lineup})
+}
+
+// GetUserLineups godoc
+// @Summary Get user's saved lineups
+// @Description List the user's saved lineups, newest first, optionally for one match
+// @Tags fantasy
+// @Produce json
+// @Security BearerAuth
+// @Param match_id query string false "Match ID"
+// @Success 200 {array} models.SavedLineup
+// @Router /fantasy/lineups [get]
+func (h *LineupHandler) GetUserLineups(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	var matchID *uuid.UUID
+	if param := c.Query("match_id"); param != "" {
+		id, err := uuid.Parse(param)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+			return
+		}
+		matchID = &id
+	}
+
+	lineups, err := h.lineupService.GetUserLineups(userModel.ID, matchID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get lineups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lineups": lineups})
+}
+
+// GetLineup godoc
+// @Summary Get a saved lineup
+// @Tags fantasy
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lineup ID"
+// @Success 200 {object} models.SavedLineup
+// @Router /fantasy/lineups/{id} [get]
+func (h *LineupHandler) GetLineup(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	lineupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lineup ID"})
+		return
+	}
+
+	lineup, err := h.lineupService.GetLineup(userModel.ID, lineupID)
+	if err != nil {
+		c.JSON(lineupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lineup": lineup})
+}
+
+// UpdateLineup godoc
+// @Summary Edit a saved lineup
+// @Description Change a saved lineup and every entry following it in a contest that has not locked. Entries that cannot take the change are listed as skipped.
+// @Tags fantasy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lineup ID"
+// @Param lineup body models.UpdateLineupRequest true "New lineup"
+// @Success 200 {object} services.LineupUpdateResult
+// @Router /fantasy/lineups/{id} [put]
+func (h *LineupHandler) UpdateLineup(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	lineupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lineup ID"})
+		return
+	}
+
+	var req models.UpdateLineupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	result, err := h.lineupService.UpdateLineup(userModel.ID, lineupID, &req)
+	if err != nil {
+		c.JSON(lineupErrorStatus(err), teamErrorBody(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CloneLineup godoc
+// @Summary Copy a saved lineup
+// @Description Copy a saved lineup into a new one that can be changed without touching the original or its entries
+// @Tags fantasy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lineup ID"
+// @Param lineup body models.CloneLineupRequest false "Name of the copy"
+// @Success 201 {object} models.SavedLineup
+// @Router /fantasy/lineups/{id}/clone [post]
+func (h *LineupHandler) CloneLineup(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	lineupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lineup ID"})
+		return
+	}
+
+	// The body is optional
+	var req models.CloneLineupRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			return
+		}
+	}
+
+	lineup, err := h.lineupService.CloneLineup(userModel.ID, lineupID, &req)
+	if err != nil {
+		c.JSON(lineupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"lineup": lineup})
+}
+
+// JoinContestsWithLineup godoc
+// @Summary Enter a saved lineup into contests
+// @Description Join each listed contest on the lineup's match with a new team following the lineup, paying each entry fee from the wallet. Each contest succeeds or fails on its own.
+// @Tags fantasy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lineup ID"
+// @Param contests body models.JoinWithLineupRequest true "Contests to join"
+// @Success 200 {array} services.LineupEntryResult
+// @Router /fantasy/lineups/{id}/join [post]
+func (h *LineupHandler) JoinContestsWithLineup(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	lineupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lineup ID"})
+		return
+	}
+
+	var req models.JoinWithLineupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	results, err := h.lineupService.JoinContests(userModel.ID, lineupID, &req)
+	if err != nil {
+		c.JSON(lineupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
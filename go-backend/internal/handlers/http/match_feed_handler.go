@@ -0,0 +1,110 @@
+package http
+
+import (
+	"net/http"
+
+	"esports-fantasy-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type MatchFeedHandler struct {
+	matchFeedService *services.MatchFeedService
+}
+
+func NewMatchFeedHandler(matchFeedService *services.MatchFeedService) *MatchFeedHandler {
+	return &MatchFeedHandler{
+		matchFeedService: matchFeedService,
+	}
+}
+
+type StartReplayRequest struct {
+	File  string  `json:"file" binding:"required"`
+	Speed float64 `json:"speed"`
+}
+
+// StartReplay replays a recorded match feed file into the scoring pipeline (admin only)
+func (h *MatchFeedHandler) StartReplay(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("matchId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid match ID",
+		})
+		return
+	}
+
+	var req StartReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.matchFeedService.StartReplay(matchID, req.File, req.Speed); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Failed to start match feed replay",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Match feed replay started successfully",
+		"data": gin.H{
+			"match_id": matchID,
+			"file":     req.File,
+			"status":   "FEED_STARTED",
+		},
+	})
+}
+
+// StopFeed stops the running feed for a match (admin only)
+func (h *MatchFeedHandler) StopFeed(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Param("matchId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid match ID",
+		})
+		return
+	}
+
+	if err := h.matchFeedService.StopFeed(matchID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Failed to stop match feed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Match feed stopped successfully",
+		"data": gin.H{
+			"match_id": matchID,
+			"status":   "FEED_STOPPED",
+		},
+	})
+}
+
+// GetActiveFeeds lists the running match feeds (admin only)
+func (h *MatchFeedHandler) GetActiveFeeds(c *gin.Context) {
+	feeds := h.matchFeedService.GetActiveFeeds()
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Active match feeds retrieved successfully",
+		"data": gin.H{
+			"feeds": feeds,
+			"count": len(feeds),
+		},
+	})
+}
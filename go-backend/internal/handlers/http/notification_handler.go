@@ -0,0 +1,89 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"esports-fantasy-backend/internal/models"
+	"esports-fantasy-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications godoc
+// @Summary Get user notifications
+// @Description Get the authenticated user's latest notifications and unread count
+// @Tags user
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of notifications" default(50)
+// @Success 200 {array} models.Notification
+// @Router /user/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	notifications, unread, err := h.notificationService.GetUserNotifications(userModel.ID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"unread":        unread,
+	})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark notification as read
+// @Description Mark one of the authenticated user's notifications as read
+// @Tags user
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} map[string]string
+// @Router /user/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := h.notificationService.MarkAsRead(notificationID, userModel.ID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
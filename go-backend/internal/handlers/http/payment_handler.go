@@ -1,20 +1,25 @@
 package http
 
 import (
+	"errors"
 	"esports-fantasy-backend/internal/models"
 	"esports-fantasy-backend/internal/services"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type PaymentHandler struct {
-	paymentService services.PaymentService
+	depositService    services.DepositService
+	paymentReconciler *services.PaymentReconciler
 }
 
-func NewPaymentHandler(paymentService services.PaymentService) *PaymentHandler {
+func NewPaymentHandler(depositService services.DepositService, paymentReconciler *services.PaymentReconciler) *PaymentHandler {
 	return &PaymentHandler{
-		paymentService: paymentService,
+		depositService:    depositService,
+		paymentReconciler: paymentReconciler,
 	}
 }
 
@@ -43,27 +48,33 @@ func (h *PaymentHandler) CreatePaymentOrder(c *gin.Context) {
 		return
 	}
 
-	if req.Currency == "" {
-		req.Currency = "INR"
+	depositReq := &models.InitiateDepositRequest{
+		Amount:  req.Amount,
+		Gateway: services.GatewayRazorpay,
+		Purpose: "Wallet top-up",
+	}
+	if req.ContestID != uuid.Nil {
+		depositReq.ContestID = &req.ContestID
 	}
 
-	order, err := h.paymentService.CreatePaymentOrder(userModel.ID, &req)
+	session, err := h.depositService.InitiateDeposit(userModel.ID, depositReq)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create payment order"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"order": order,
-		"key":   "rzp_test_dummy_key_id", // Dummy key for testing
-		"message": "Payment order created successfully",
-		"note":    "This is a test payment order with dummy Razorpay integration",
+		"order":          session.Data,
+		"order_id":       session.OrderID,
+		"transaction_id": session.TransactionID,
+		"key":            session.PublicKey,
+		"message":        "Payment order created successfully",
 	})
 }
 
 // HandlePaymentSuccess godoc
 // @Summary Handle payment success
-// @Description Handle successful payment callback
+// @Description Verify a Razorpay Checkout payment and credit the wallet
 // @Tags payment
 // @Accept json
 // @Produce json
@@ -72,48 +83,150 @@ func (h *PaymentHandler) CreatePaymentOrder(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Router /payment/success [post]
 func (h *PaymentHandler) HandlePaymentSuccess(c *gin.Context) {
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+	var req struct {
+		PaymentID string `json:"razorpay_payment_id" binding:"required"`
+		OrderID   string `json:"razorpay_order_id" binding:"required"`
+		Signature string `json:"razorpay_signature"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Payment and order IDs are required"})
 		return
 	}
 
-	userModel := user.(*models.User)
-
-	var req map[string]interface{}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+	transaction, err := h.depositService.HandleCallback(services.GatewayRazorpay, &services.PaymentCallback{
+		OrderID:   req.OrderID,
+		PaymentID: req.PaymentID,
+		Signature: req.Signature,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidCallback) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": "Failed to process payment"})
 		return
 	}
 
-	paymentID, ok := req["razorpay_payment_id"].(string)
-	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Payment ID is required"})
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Payment processed successfully",
+		"amount":         transaction.Amount,
+		"status":         transaction.Status,
+		"transaction_id": transaction.ID,
+	})
+}
+
+// RefundDeposit godoc
+// @Summary Refund a deposit
+// @Description Return a completed deposit to its payment method (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Deposit transaction ID"
+// @Param refund body models.RefundDepositRequest true "Refund reason"
+// @Success 200 {object} models.Transaction
+// @Router /admin/payments/{id}/refund [post]
+func (h *PaymentHandler) RefundDeposit(c *gin.Context) {
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
 		return
 	}
 
-	orderID, ok := req["razorpay_order_id"].(string)
-	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Order ID is required"})
+	var req models.RefundDepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Refund reason is required"})
 		return
 	}
 
-	// For dummy payment, assume ₹100 
-	amount := 100.0
-	if amountVal, exists := req["amount"]; exists {
-		if amountFloat, ok := amountVal.(float64); ok {
-			amount = amountFloat
+	refund, err := h.depositService.RefundDeposit(transactionID, req.Reason)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrDepositNotRefundable) || errors.Is(err, services.ErrInsufficientFunds) {
+			status = http.StatusBadRequest
 		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
 	}
 
-	if err := h.paymentService.HandlePaymentSuccess(paymentID, orderID, userModel.ID, amount); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process payment"})
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Deposit refunded successfully",
+		"refund":  refund,
+	})
+}
+
+// ReconcilePendingDeposits godoc
+// @Summary Sweep pending deposits
+// @Description Check stuck pending deposits with their gateways and expire abandoned ones (admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} services.DepositSweepResult
+// @Router /admin/payments/reconcile-pending [post]
+func (h *PaymentHandler) ReconcilePendingDeposits(c *gin.Context) {
+	result, err := h.paymentReconciler.ReconcilePending()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Payment processed successfully",
-		"amount":  amount,
-		"status":  "success",
+		"message": "Pending deposits reconciled",
+		"result":  result,
 	})
-}
\ No newline at end of file
+}
+
+// GetReconciliationReport godoc
+// @Summary Get payment reconciliation report
+// @Description Get the stored deposit reconciliation report for a day (admin only)
+// @Tags admin
+// @Produce json
+// @Param date query string false "Report date (YYYY-MM-DD), defaults to yesterday"
+// @Success 200 {object} models.PaymentReconciliationReport
+// @Router /admin/payments/reconciliation [get]
+func (h *PaymentHandler) GetReconciliationReport(c *gin.Context) {
+	day, err := reportDay(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	report, err := h.paymentReconciler.GetDailyReport(day)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reconciliation report not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GenerateReconciliationReport godoc
+// @Summary Generate payment reconciliation report
+// @Description Check a day's deposits against the gateways and store the report (admin only)
+// @Tags admin
+// @Produce json
+// @Param date query string false "Report date (YYYY-MM-DD), defaults to yesterday"
+// @Success 200 {object} models.PaymentReconciliationReport
+// @Router /admin/payments/reconciliation [post]
+func (h *PaymentHandler) GenerateReconciliationReport(c *gin.Context) {
+	day, err := reportDay(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	report, err := h.paymentReconciler.GenerateDailyReport(day)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// reportDay reads the date query parameter, defaulting to yesterday
+func reportDay(c *gin.Context) (time.Time, error) {
+	date := c.Query("date")
+	if date == "" {
+		return time.Now().AddDate(0, 0, -1), nil
+	}
+	return time.ParseInLocation("2006-01-02", date, time.Local)
+}
@@ -1,20 +1,23 @@
 package http
 
 import (
+        "errors"
         "net/http"
 
+        "esports-fantasy-backend/internal/models"
         "esports-fantasy-backend/internal/services"
 
         "github.com/gin-gonic/gin"
+        "github.com/google/uuid"
 )
 
 type PhonePeHandler struct {
-        phonePeService *services.PhonePeService
+        depositService services.DepositService
 }
 
-func NewPhonePeHandler(phonePeService *services.PhonePeService) *PhonePeHandler {
+func NewPhonePeHandler(depositService services.DepositService) *PhonePeHandler {
         return &PhonePeHandler{
-                phonePeService: phonePeService,
+                depositService: depositService,
         }
 }
 
@@ -26,14 +29,15 @@ type PaymentRequest struct {
 
 // InitiatePayment initiates PhonePe payment
 func (h *PhonePeHandler) InitiatePayment(c *gin.Context) {
-        userID := c.GetString("user_id")
-        if userID == "" {
+        user, exists := c.Get("user")
+        if !exists {
                 c.JSON(http.StatusUnauthorized, gin.H{
                         "success": false,
                         "message": "Authentication required",
                 })
                 return
         }
+        userID := user.(*models.User).ID
 
         var req PaymentRequest
         if err := c.ShouldBindJSON(&req); err != nil {
@@ -45,7 +49,16 @@ func (h *PhonePeHandler) InitiatePayment(c *gin.Context) {
                 return
         }
 
-        response, err := h.phonePeService.InitiatePayment(userID, req.Amount, req.ContestID, req.Purpose)
+        depositReq := &models.InitiateDepositRequest{
+                Amount:  req.Amount,
+                Gateway: services.GatewayPhonePe,
+                Purpose: req.Purpose,
+        }
+        if contestID, err := uuid.Parse(req.ContestID); err == nil {
+                depositReq.ContestID = &contestID
+        }
+
+        session, err := h.depositService.InitiateDeposit(userID, depositReq)
         if err != nil {
                 c.JSON(http.StatusInternalServerError, gin.H{
                         "success": false,
@@ -55,7 +68,11 @@ func (h *PhonePeHandler) InitiatePayment(c *gin.Context) {
                 return
         }
 
-        c.JSON(http.StatusOK, response)
+        c.JSON(http.StatusOK, gin.H{
+                "success": true,
+                "message": "Payment initiated successfully",
+                "data":    session,
+        })
 }
 
 // HandleCallback handles PhonePe payment callback
@@ -71,8 +88,16 @@ func (h *PhonePeHandler) HandleCallback(c *gin.Context) {
                 return
         }
 
-        if err := h.phonePeService.HandleCallback(base64Response, checksum); err != nil {
-                c.JSON(http.StatusBadRequest, gin.H{
+        _, err := h.depositService.HandleCallback(services.GatewayPhonePe, &services.PaymentCallback{
+                Payload:   base64Response,
+                Signature: checksum,
+        })
+        if err != nil {
+                status := http.StatusInternalServerError
+                if errors.Is(err, services.ErrInvalidCallback) || errors.Is(err, services.ErrPaymentAmountMismatch) {
+                        status = http.StatusBadRequest
+                }
+                c.JSON(status, gin.H{
                         "success": false,
                         "message": "Failed to process callback",
                         "error":   err.Error(),
@@ -88,16 +113,16 @@ func (h *PhonePeHandler) HandleCallback(c *gin.Context) {
 
 // CheckPaymentStatus checks payment status
 func (h *PhonePeHandler) CheckPaymentStatus(c *gin.Context) {
-        txnID := c.Param("txnId")
-        if txnID == "" {
+        txnID, err := uuid.Parse(c.Param("txnId"))
+        if err != nil {
                 c.JSON(http.StatusBadRequest, gin.H{
                         "success": false,
-                        "message": "Transaction ID is required",
+                        "message": "Invalid transaction ID",
                 })
                 return
         }
 
-        response, err := h.phonePeService.CheckPaymentStatus(txnID)
+        transaction, err := h.depositService.CheckStatus(txnID)
         if err != nil {
                 c.JSON(http.StatusInternalServerError, gin.H{
                         "success": false,
@@ -107,5 +132,14 @@ func (h *PhonePeHandler) CheckPaymentStatus(c *gin.Context) {
                 return
         }
 
-        c.JSON(http.StatusOK, response)
+        c.JSON(http.StatusOK, gin.H{
+                "success": true,
+                "message": "Payment status retrieved",
+                "data": gin.H{
+                        "transaction_id": transaction.ID,
+                        "status":         transaction.Status,
+                        "amount":         transaction.Amount,
+                        "payment_id":     transaction.PaymentID,
+                },
+        })
 }
\ No newline at end of file
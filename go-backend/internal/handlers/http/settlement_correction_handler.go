@@ -0,0 +1,180 @@
+package http
+
+import (
+	"net/http"
+
+	"esports-fantasy-backend/internal/models"
+	"esports-fantasy-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SettlementCorrectionHandler struct {
+	correctionService services.SettlementCorrectionService
+}
+
+func NewSettlementCorrectionHandler(correctionService services.SettlementCorrectionService) *SettlementCorrectionHandler {
+	return &SettlementCorrectionHandler{
+		correctionService: correctionService,
+	}
+}
+
+// ProposeCorrection rescores a settled contest and records the prize deltas for approval (admin only)
+func (h *SettlementCorrectionHandler) ProposeCorrection(c *gin.Context) {
+	contestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid contest ID",
+		})
+		return
+	}
+
+	var req models.CreateSettlementCorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	correction, err := h.correctionService.ProposeCorrection(contestID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Failed to propose settlement correction",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Settlement correction proposed, awaiting approval",
+		"data":    correction,
+	})
+}
+
+// GetContestCorrections lists the settlement corrections of a contest (admin only)
+func (h *SettlementCorrectionHandler) GetContestCorrections(c *gin.Context) {
+	contestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid contest ID",
+		})
+		return
+	}
+
+	corrections, err := h.correctionService.GetContestCorrections(contestID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get settlement corrections",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Settlement corrections retrieved successfully",
+		"data":    corrections,
+	})
+}
+
+// GetCorrection returns a settlement correction with its per-user entries (admin only)
+func (h *SettlementCorrectionHandler) GetCorrection(c *gin.Context) {
+	correctionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid correction ID",
+		})
+		return
+	}
+
+	correction, err := h.correctionService.GetCorrection(correctionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Settlement correction not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Settlement correction retrieved successfully",
+		"data":    correction,
+	})
+}
+
+// ApproveCorrection posts the credits and clawbacks of a pending correction (admin only)
+func (h *SettlementCorrectionHandler) ApproveCorrection(c *gin.Context) {
+	correctionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid correction ID",
+		})
+		return
+	}
+
+	correction, err := h.correctionService.ApproveCorrection(correctionID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Failed to approve settlement correction",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Settlement correction applied successfully",
+		"data":    correction,
+	})
+}
+
+// RejectCorrection discards a pending correction without moving any money (admin only)
+func (h *SettlementCorrectionHandler) RejectCorrection(c *gin.Context) {
+	correctionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid correction ID",
+		})
+		return
+	}
+
+	var req models.RejectSettlementCorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	correction, err := h.correctionService.RejectCorrection(correctionID, req.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Failed to reject settlement correction",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Settlement correction rejected",
+		"data":    correction,
+	})
+}
@@ -59,11 +59,11 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 
 // GetWalletBalance godoc
 // @Summary Get user wallet balance
-// @Description Get the current wallet balance for authenticated user
+// @Description Get the current wallet balance for authenticated user, split into deposit, winnings and bonus buckets
 // @Tags user
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]float64
+// @Success 200 {object} models.WalletBalanceResponse
 // @Router /user/wallet [get]
 func (h *UserHandler) GetWalletBalance(c *gin.Context) {
 	user, exists := c.Get("user")
@@ -74,14 +74,18 @@ func (h *UserHandler) GetWalletBalance(c *gin.Context) {
 
 	userModel := user.(*models.User)
 
-	balance, err := h.userService.GetWalletBalance(userModel.ID)
+	wallet, err := h.userService.GetWallet(userModel.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet balance"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"balance": balance,
-		"user_id": userModel.ID,
+		"balance":      wallet.Balance,
+		"deposit":      wallet.Deposit,
+		"winnings":     wallet.Winnings,
+		"bonus":        wallet.Bonus,
+		"withdrawable": wallet.Withdrawable,
+		"user_id":      userModel.ID,
 	})
 }
\ No newline at end of file
@@ -0,0 +1,207 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"esports-fantasy-backend/internal/models"
+	"esports-fantasy-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WithdrawalHandler struct {
+	withdrawalService services.WithdrawalService
+}
+
+func NewWithdrawalHandler(withdrawalService services.WithdrawalService) *WithdrawalHandler {
+	return &WithdrawalHandler{
+		withdrawalService: withdrawalService,
+	}
+}
+
+// RequestWithdrawal holds part of the user's winnings for payout once an admin approves it
+func (h *WithdrawalHandler) RequestWithdrawal(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	var req models.CreateWithdrawalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	withdrawal, err := h.withdrawalService.RequestWithdrawal(userModel.ID, &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrWithdrawalBelowMinimum) ||
+			errors.Is(err, services.ErrWithdrawalLimitExceeded) ||
+			errors.Is(err, services.ErrInsufficientFunds) {
+			status = http.StatusBadRequest
+		}
+		if errors.Is(err, services.ErrWithdrawalsUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": "Failed to request withdrawal",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Withdrawal requested, awaiting approval",
+		"data":    withdrawal,
+	})
+}
+
+// GetMyWithdrawals lists the user's withdrawals, newest first
+func (h *WithdrawalHandler) GetMyWithdrawals(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	userModel := user.(*models.User)
+
+	withdrawals, err := h.withdrawalService.GetUserWithdrawals(userModel.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get withdrawals",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Withdrawals retrieved successfully",
+		"data":    withdrawals,
+	})
+}
+
+// GetWithdrawals lists withdrawals by status, defaulting to the review queue (admin only)
+func (h *WithdrawalHandler) GetWithdrawals(c *gin.Context) {
+	withdrawals, err := h.withdrawalService.GetWithdrawals(c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get withdrawals",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Withdrawals retrieved successfully",
+		"data":    withdrawals,
+	})
+}
+
+// ApproveWithdrawal pays out a requested withdrawal (admin only)
+func (h *WithdrawalHandler) ApproveWithdrawal(c *gin.Context) {
+	withdrawalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid withdrawal ID",
+		})
+		return
+	}
+
+	withdrawal, err := h.withdrawalService.ApproveWithdrawal(withdrawalID, reviewerID(c))
+	if err != nil {
+		c.JSON(withdrawalErrorStatus(err), gin.H{
+			"success": false,
+			"message": "Failed to approve withdrawal",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	message := "Withdrawal paid out successfully"
+	if withdrawal.Status == services.WithdrawalStatusFailed {
+		message = "Payout failed, funds returned to the user's winnings"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": withdrawal.Status == services.WithdrawalStatusPaid,
+		"message": message,
+		"data":    withdrawal,
+	})
+}
+
+// RejectWithdrawal turns down a requested withdrawal and releases the held funds (admin only)
+func (h *WithdrawalHandler) RejectWithdrawal(c *gin.Context) {
+	withdrawalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid withdrawal ID",
+		})
+		return
+	}
+
+	var req models.RejectWithdrawalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	withdrawal, err := h.withdrawalService.RejectWithdrawal(withdrawalID, reviewerID(c), req.Reason)
+	if err != nil {
+		c.JSON(withdrawalErrorStatus(err), gin.H{
+			"success": false,
+			"message": "Failed to reject withdrawal",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Withdrawal rejected",
+		"data":    withdrawal,
+	})
+}
+
+// reviewerID returns the signed-in admin, if any
+func reviewerID(c *gin.Context) *uuid.UUID {
+	user, exists := c.Get("user")
+	if !exists {
+		return nil
+	}
+	if userModel, ok := user.(*models.User); ok {
+		return &userModel.ID
+	}
+	return nil
+}
+
+func withdrawalErrorStatus(err error) int {
+	if errors.Is(err, services.ErrWithdrawalNotPending) {
+		return http.StatusConflict
+	}
+	if errors.Is(err, services.ErrWithdrawalsUnavailable) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusBadRequest
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,7 +14,10 @@ type User struct {
 	Name            string         `json:"name"`
 	Username        string         `json:"username" gorm:"unique;not null"`
 	ProfileImage    string         `json:"profile_image" gorm:"type:text"` // Base64 encoded image
-	WalletBalance   float64        `json:"wallet_balance" gorm:"default:0.00"`
+	WalletBalance   float64        `json:"wallet_balance" gorm:"default:0.00;<-:create"` // Cached from the ledger; only LedgerService writes it, with raw SQL
+	DepositBalance  float64        `json:"deposit_balance" gorm:"default:0.00;<-:create"`  // Added money, playable but not withdrawable
+	WinningsBalance float64        `json:"winnings_balance" gorm:"default:0.00;<-:create"` // Prizes, the only withdrawable bucket
+	BonusBalance    float64        `json:"bonus_balance" gorm:"default:0.00;<-:create"`    // Promotional credit
 	IsAdmin         bool           `json:"is_admin" gorm:"default:false"`
 	IsVerified      bool           `json:"is_verified" gorm:"default:false"`
 	ReferralCode    string         `json:"referral_code" gorm:"unique"`
@@ -87,30 +91,50 @@ type Contest struct {
 	EntryFee          float64   `json:"entry_fee" gorm:"not null"`
 	PrizePool         string    `json:"prize_pool" gorm:"type:jsonb"` // JSON structure for prize distribution
 	MaxEntries        int       `json:"max_entries" gorm:"not null"`
+	MinEntries        int       `json:"min_entries" gorm:"default:0"` // Cancelled and refunded if fewer join by lock time; 0 for no minimum
+	MaxEntriesPerUser int       `json:"max_entries_per_user" gorm:"default:1"` // Teams one user may enter
 	CurrentEntries    int       `json:"current_entries" gorm:"default:0"`
 	IsPrivate         bool      `json:"is_private" gorm:"default:false"`
 	InviteCode        string    `json:"invite_code" gorm:"unique"`
 	Status            string    `json:"status" gorm:"default:open"` // open, locked, completed, cancelled
 	LockedAt          *time.Time `json:"locked_at"`
+	ScoringRulesetID  *uuid.UUID `json:"scoring_ruleset_id"` // Ruleset pinned when the contest locks
 	PrizesDistributed bool      `json:"prizes_distributed" gorm:"default:false"`
+	CancelledAt        *time.Time `json:"cancelled_at,omitempty"`
+	CancellationReason string     `json:"cancellation_reason,omitempty"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 type FantasyTeam struct {
 	ID            uuid.UUID              `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	UserID        uuid.UUID              `json:"user_id"`
+	UserID        uuid.UUID              `json:"user_id" gorm:"uniqueIndex:idx_fantasy_team_entry"`
 	User          User                   `json:"user" gorm:"foreignKey:UserID"`
-	ContestID     uuid.UUID              `json:"contest_id"`
+	ContestID     uuid.UUID              `json:"contest_id" gorm:"uniqueIndex:idx_fantasy_team_entry"`
 	Contest       Contest                `json:"contest" gorm:"foreignKey:ContestID"`
 	TeamName      string                 `json:"team_name"`
+	EntryNumber   int                    `json:"entry_number" gorm:"default:1;uniqueIndex:idx_fantasy_team_entry"` // Nth team of the user in the contest
+	EntryLabel    string                 `json:"entry_label" gorm:"-"`                                              // T1..Tn, from EntryNumber
+	SavedLineupID *uuid.UUID             `json:"saved_lineup_id" gorm:"index"`                                      // Lineup the team follows; nil once edited on its own
 	Players       []FantasyTeamPlayer    `json:"players" gorm:"foreignKey:FantasyTeamID"`
 	TotalPoints   float64                `json:"total_points" gorm:"default:0.00"`
 	Rank          *int                   `json:"rank"`
+	IsTied        bool                   `json:"is_tied" gorm:"default:false"` // Shares its final rank with another team
 	CreatedAt     time.Time              `json:"created_at"`
 	UpdatedAt     time.Time              `json:"updated_at"`
 }
 
+// EntryLabel names a user's nth team in a contest: T1, T2, ...
+func EntryLabel(entryNumber int) string {
+	return fmt.Sprintf("T%d", entryNumber)
+}
+
+// AfterFind fills in the entry label, which is not stored
+func (t *FantasyTeam) AfterFind(tx *gorm.DB) error {
+	t.EntryLabel = EntryLabel(t.EntryNumber)
+	return nil
+}
+
 type FantasyTeamPlayer struct {
 	FantasyTeamID  uuid.UUID   `json:"fantasy_team_id" gorm:"primaryKey"`
 	FantasyTeam    FantasyTeam `json:"fantasy_team" gorm:"foreignKey:FantasyTeamID"`
@@ -120,6 +144,71 @@ type FantasyTeamPlayer struct {
 	IsViceCaptain  bool        `json:"is_vice_captain" gorm:"default:false"`
 }
 
+// SavedLineup - A user's lineup for a match, kept apart from any contest so
+// it can be entered into several contests and edited in one place
+type SavedLineup struct {
+	ID        uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID           `json:"user_id" gorm:"index"`
+	MatchID   uuid.UUID           `json:"match_id" gorm:"index"`
+	Match     Match               `json:"-" gorm:"foreignKey:MatchID"`
+	Name      string              `json:"name" gorm:"not null"`
+	Players   []SavedLineupPlayer `json:"players" gorm:"foreignKey:SavedLineupID"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+type SavedLineupPlayer struct {
+	SavedLineupID uuid.UUID `json:"saved_lineup_id" gorm:"primaryKey"`
+	PlayerID      uuid.UUID `json:"player_id" gorm:"primaryKey"`
+	Player        Player    `json:"player" gorm:"foreignKey:PlayerID"`
+	IsCaptain     bool      `json:"is_captain" gorm:"default:false"`
+	IsViceCaptain bool      `json:"is_vice_captain" gorm:"default:false"`
+}
+
+// FantasyTeamLineup - A team's players, captain and vice-captain at one point
+type FantasyTeamLineup struct {
+	TeamName      string      `json:"team_name"`
+	PlayerIDs     []uuid.UUID `json:"player_ids"`
+	CaptainID     uuid.UUID   `json:"captain_id"`
+	ViceCaptainID uuid.UUID   `json:"vice_captain_id"`
+}
+
+// FantasyTeamEdit - One change a user made to their team before the lock
+type FantasyTeamEdit struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	FantasyTeamID  uuid.UUID `json:"fantasy_team_id" gorm:"index"`
+	UserID         uuid.UUID `json:"user_id"`
+	PreviousLineup string    `json:"previous_lineup" gorm:"type:jsonb"` // FantasyTeamLineup
+	NewLineup      string    `json:"new_lineup" gorm:"type:jsonb"`      // FantasyTeamLineup
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ContestEntry - A paid place in a contest, held by one fantasy team
+type ContestEntry struct {
+	ID            uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ContestID     uuid.UUID   `json:"contest_id" gorm:"index"`
+	UserID        uuid.UUID   `json:"user_id" gorm:"index"`
+	FantasyTeamID uuid.UUID   `json:"fantasy_team_id" gorm:"uniqueIndex"`
+	FantasyTeam   FantasyTeam `json:"fantasy_team" gorm:"foreignKey:FantasyTeamID"`
+	TransactionID *uuid.UUID  `json:"transaction_id"` // Entry fee debit; nil for free contests
+	EntryFee      float64     `json:"entry_fee"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// PendingContestJoin - Contest join waiting on the deposit that pays for it
+type PendingContestJoin struct {
+	ID                   uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID               uuid.UUID  `json:"user_id" gorm:"index"`
+	ContestID            uuid.UUID  `json:"contest_id"`
+	DepositTransactionID uuid.UUID  `json:"deposit_transaction_id" gorm:"uniqueIndex"`
+	TeamRequest          string     `json:"team_request" gorm:"type:jsonb"` // CreateTeamRequest to join with
+	Status               string     `json:"status" gorm:"default:pending"`  // pending, joined, failed
+	ContestEntryID       *uuid.UUID `json:"contest_entry_id"`
+	FailureReason        string     `json:"failure_reason,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
 type PlayerMatchStats struct {
 	ID                   uint      `json:"id" gorm:"primaryKey;autoIncrement"`
 	PlayerID            uuid.UUID `json:"player_id"`
@@ -132,25 +221,245 @@ type PlayerMatchStats struct {
 	SurvivalTimeMinutes int       `json:"survival_time_minutes" gorm:"default:0"`
 	IsMVP               bool      `json:"is_mvp" gorm:"default:false"`
 	TeamKillPenalty     int       `json:"team_kill_penalty" gorm:"default:0"`
+	Placement           int       `json:"placement" gorm:"default:0"` // Squad placement, 0 until results are in
 	TotalPoints         float64   `json:"total_points" gorm:"default:0.00"`
+	PointsBreakdown     string    `json:"points_breakdown" gorm:"type:jsonb"` // JSON array of PointsBreakdownItem
 	CreatedAt           time.Time `json:"created_at"`
 	UpdatedAt           time.Time `json:"updated_at"`
 }
 
+// MatchStatEvent - Append-only log entry that PlayerMatchStats are folded from.
+// Corrections are recorded as compensating events, never as edits.
+type MatchStatEvent struct {
+	ID                 uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MatchID            uuid.UUID  `json:"match_id" gorm:"index:idx_match_stat_event_match_player"`
+	PlayerID           uuid.UUID  `json:"player_id" gorm:"index:idx_match_stat_event_match_player"`
+	ActionType         string     `json:"action_type" gorm:"not null"` // kill, knockout, revive, survival_minute, mvp, team_kill
+	Value              int        `json:"value" gorm:"not null"`
+	OccurredAt         time.Time  `json:"occurred_at" gorm:"not null"`
+	Source             string     `json:"source" gorm:"not null"` // admin, feed, correction, baseline
+	CompensatesEventID *uuid.UUID `json:"compensates_event_id" gorm:"type:uuid;uniqueIndex"`
+	Reason             string     `json:"reason"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// PointsBreakdownItem - One line of a player's itemised fantasy points
+type PointsBreakdownItem struct {
+	ActionType      string  `json:"action_type"`
+	Count           float64 `json:"count"`
+	PointsPerAction float64 `json:"points_per_action"`
+	Points          float64 `json:"points"`
+}
+
+// PlayerPointsBreakdown - Why a player scored what they scored in a match
+type PlayerPointsBreakdown struct {
+	PlayerID    uuid.UUID             `json:"player_id"`
+	PlayerName  string                `json:"player_name"`
+	MatchID     uuid.UUID             `json:"match_id"`
+	ContestID   *uuid.UUID            `json:"contest_id,omitempty"` // Contest whose pinned ruleset scored the points; empty for the match's current rules
+	Items       []PointsBreakdownItem `json:"items"`
+	BasePoints  float64               `json:"base_points"`
+	Role        string                `json:"role,omitempty"` // captain, vice_captain
+	Multiplier  float64               `json:"multiplier"`
+	TotalPoints float64               `json:"total_points"`
+}
+
+// FantasyTeamPointsBreakdown - Per-player breakdown of a fantasy team's total
+type FantasyTeamPointsBreakdown struct {
+	FantasyTeamID uuid.UUID               `json:"fantasy_team_id"`
+	TeamName      string                  `json:"team_name"`
+	ContestID     uuid.UUID               `json:"contest_id"`
+	MatchID       uuid.UUID               `json:"match_id"`
+	Players       []PlayerPointsBreakdown `json:"players"`
+	TotalPoints   float64                 `json:"total_points"`
+}
+
 type Transaction struct {
 	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	UserID          uuid.UUID `json:"user_id"`
 	User            User      `json:"user" gorm:"foreignKey:UserID"`
-	Amount          float64   `json:"amount" gorm:"not null"`
+	Amount          float64   `json:"amount" gorm:"not null"` // Always positive, like the bucket amounts; Type gives the direction
 	Type            string    `json:"type" gorm:"not null"` // deposit, withdrawal, contest_entry, winnings
 	Status          string    `json:"status" gorm:"default:pending"` // pending, completed, failed
 	RelatedEntityID *uuid.UUID `json:"related_entity_id"` // contest_id or other reference
-	PaymentID       string    `json:"payment_id"` // Razorpay payment ID
-	OrderID         string    `json:"order_id"`   // Razorpay order ID
+	Gateway         string    `json:"gateway,omitempty" gorm:"index"` // Payment gateway that collected a deposit, e.g. phonepe
+	PaymentID       string    `json:"payment_id"` // Gateway payment ID
+	OrderID         string    `json:"order_id" gorm:"index"` // Gateway order ID
+	ReasonCode      string    `json:"reason_code,omitempty"` // Why an adjustment was posted, e.g. stat_correction
+	Description     string    `json:"description,omitempty"`
+	Bucket          string    `json:"bucket,omitempty"` // deposit, winnings, bonus, or mixed when several paid
+	DepositAmount   float64   `json:"deposit_amount"`
+	WinningsAmount  float64   `json:"winnings_amount"`
+	BonusAmount     float64   `json:"bonus_amount"`
+	IdempotencyKey  *string   `json:"-" gorm:"uniqueIndex"` // Guards against posting the same credit twice
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
+// GatewayCallback - Payment gateway notification as received, kept for audit
+// and to trace retried or disputed callbacks
+type GatewayCallback struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Gateway       string     `json:"gateway" gorm:"not null"`
+	TransactionID *uuid.UUID `json:"transaction_id" gorm:"index"` // Deposit the callback matched, if any
+	OrderID       string     `json:"order_id" gorm:"index"`       // merchantTransactionId for PhonePe
+	PaymentID     string     `json:"payment_id"`
+	GatewayState  string     `json:"gateway_state"`
+	Amount        float64    `json:"amount"`
+	Payload       string     `json:"payload" gorm:"type:text"` // Raw body, e.g. PhonePe's base64 response
+	Signature     string     `json:"signature"`
+	Outcome       string     `json:"outcome" gorm:"index"` // applied, pending, duplicate, conflict, amount_mismatch, rejected
+	Error         string     `json:"error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// PaymentReconciliationReport - Daily comparison of deposits in our ledger
+// with what the gateways report for them
+type PaymentReconciliationReport struct {
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ReportDate      string    `json:"report_date" gorm:"uniqueIndex;not null"` // YYYY-MM-DD the deposits were made
+	DepositsChecked int       `json:"deposits_checked"`
+	Matched         int       `json:"matched"`
+	MismatchCount   int       `json:"mismatch_count"`
+	LedgerTotal     float64   `json:"ledger_total"`  // Deposits credited to wallets
+	GatewayTotal    float64   `json:"gateway_total"` // Deposits the gateways report as paid
+	Mismatches      string    `json:"mismatches" gorm:"type:jsonb"`
+	GeneratedAt     time.Time `json:"generated_at"`
+}
+
+// Notification - In-app message to a user about their account or contests
+type Notification struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `json:"user_id" gorm:"index"`
+	Type      string    `json:"type" gorm:"not null"` // prize_adjustment, refund, withdrawal, etc.
+	Title     string    `json:"title" gorm:"not null"`
+	Message   string    `json:"message"`
+	Data      string    `json:"data" gorm:"type:jsonb"` // JSON payload for the client
+	IsRead    bool      `json:"is_read" gorm:"default:false"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LedgerAccount - Account in the double-entry wallet ledger. User accounts
+// are per user; platform accounts have no UserID.
+type LedgerAccount struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Code      string     `json:"code" gorm:"uniqueIndex;not null"` // e.g. user_winnings:<user id>, platform_rake
+	Type      string     `json:"type" gorm:"index;not null"`       // user_deposit, user_winnings, user_bonus, withdrawal_hold, platform_rake, prize_escrow, gateway_clearing
+	UserID    *uuid.UUID `json:"user_id" gorm:"index"`
+	Balance   float64    `json:"balance" gorm:"default:0.00"` // Cached, in the account's normal direction
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// JournalEntry - Balanced set of ledger postings for one business event
+type JournalEntry struct {
+	ID             uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Kind           string        `json:"kind" gorm:"index;not null"` // deposit, contest_entry, prize, referral_bonus, etc.
+	ReferenceID    *uuid.UUID    `json:"reference_id" gorm:"index"`  // Contest, league or other related entity
+	TransactionID  *uuid.UUID    `json:"transaction_id" gorm:"index"`
+	IdempotencyKey *string       `json:"-" gorm:"uniqueIndex"`
+	Description    string        `json:"description"`
+	Lines          []JournalLine `json:"lines" gorm:"foreignKey:JournalEntryID"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// JournalLine - Debit or credit to one account within a journal entry
+type JournalLine struct {
+	ID             uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	JournalEntryID uuid.UUID     `json:"journal_entry_id" gorm:"index"`
+	AccountID      uuid.UUID     `json:"account_id" gorm:"index"`
+	Account        LedgerAccount `json:"account" gorm:"foreignKey:AccountID"`
+	Debit          float64       `json:"debit" gorm:"default:0.00"`
+	Credit         float64       `json:"credit" gorm:"default:0.00"`
+}
+
+// ContestSettlement - Prize payout run for a contest. The payouts are fixed
+// when the settlement is planned so a re-run only completes unpaid rows.
+type ContestSettlement struct {
+	ID            uuid.UUID          `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ContestID     uuid.UUID          `json:"contest_id" gorm:"uniqueIndex"`
+	Contest       Contest            `json:"-" gorm:"foreignKey:ContestID"`
+	SettlementKey string             `json:"settlement_key" gorm:"uniqueIndex;not null"`
+	Status        string             `json:"status" gorm:"default:pending"` // pending, completed
+	Participants  int                `json:"participants"`
+	PrizePool     float64            `json:"prize_pool"`
+	TotalPaid     float64            `json:"total_paid"`
+	CompletedAt   *time.Time         `json:"completed_at"`
+	Payouts       []SettlementPayout `json:"payouts" gorm:"foreignKey:SettlementID"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// SettlementPayout - Prize owed to one fantasy team in a settlement
+type SettlementPayout struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SettlementID  uuid.UUID  `json:"settlement_id" gorm:"uniqueIndex:idx_settlement_payout_team"`
+	FantasyTeamID uuid.UUID  `json:"fantasy_team_id" gorm:"uniqueIndex:idx_settlement_payout_team"`
+	UserID        uuid.UUID  `json:"user_id" gorm:"index"`
+	Rank          int        `json:"rank"`
+	IsTied        bool       `json:"is_tied"`
+	Amount        float64    `json:"amount"`
+	Status        string     `json:"status" gorm:"default:pending"` // pending, paid
+	TransactionID *uuid.UUID `json:"transaction_id"`
+	PaidAt        *time.Time `json:"paid_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// SettlementCorrection - Admin-approved re-settlement of a paid-out contest
+// after its stats were corrected
+type SettlementCorrection struct {
+	ID             uuid.UUID                   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ContestID      uuid.UUID                   `json:"contest_id" gorm:"index"`
+	Contest        Contest                     `json:"-" gorm:"foreignKey:ContestID"`
+	Status         string                      `json:"status" gorm:"default:pending"` // pending, applied, rejected
+	ReasonCode     string                      `json:"reason_code" gorm:"not null"`
+	Reason         string                      `json:"reason"`
+	TotalCredit    float64                     `json:"total_credit"`
+	TotalClawback  float64                     `json:"total_clawback"`
+	TeamTotals     string                      `json:"-" gorm:"type:jsonb"` // Rescored total points per fantasy team, written on approval
+	RejectedReason string                      `json:"rejected_reason,omitempty"`
+	AppliedAt      *time.Time                  `json:"applied_at"`
+	Entries        []SettlementCorrectionEntry `json:"entries" gorm:"foreignKey:CorrectionID"`
+	CreatedAt      time.Time                   `json:"created_at"`
+	UpdatedAt      time.Time                   `json:"updated_at"`
+}
+
+// SettlementCorrectionEntry - One user's rank and prize change in a correction
+type SettlementCorrectionEntry struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CorrectionID  uuid.UUID  `json:"correction_id" gorm:"index"`
+	UserID        uuid.UUID  `json:"user_id"`
+	OldRank       *int       `json:"old_rank"`
+	NewRank       int        `json:"new_rank"`
+	OldPrize      float64    `json:"old_prize"`
+	NewPrize      float64    `json:"new_prize"`
+	Delta         float64    `json:"delta"` // positive is a credit, negative a clawback
+	TransactionID *uuid.UUID `json:"transaction_id"`
+}
+
+// Withdrawal - User request to cash out winnings. The amount is held from the
+// winnings bucket until the payout succeeds or the request is turned down.
+type Withdrawal struct {
+	ID                uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID            uuid.UUID  `json:"user_id" gorm:"index"`
+	User              User       `json:"-" gorm:"foreignKey:UserID"`
+	Amount            float64    `json:"amount" gorm:"not null"`
+	Status            string     `json:"status" gorm:"index;default:requested"` // requested, processing, paid, rejected, failed
+	Method            string     `json:"method" gorm:"not null"`                // upi, bank_transfer
+	Destination       string     `json:"destination" gorm:"not null"`           // UPI ID or account number and IFSC
+	TransactionID     *uuid.UUID `json:"transaction_id"`
+	Provider          string     `json:"provider,omitempty"`
+	ProviderReference string     `json:"provider_reference,omitempty"`
+	FailureReason     string     `json:"failure_reason,omitempty"`
+	RejectedReason    string     `json:"rejected_reason,omitempty"`
+	ReviewedBy        *uuid.UUID `json:"reviewed_by"`
+	ReviewedAt        *time.Time `json:"reviewed_at"`
+	PaidAt            *time.Time `json:"paid_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
 // Request/Response DTOs
 type LoginRequest struct {
 	PhoneNumber string `json:"phone_number" binding:"required"`
@@ -162,11 +471,53 @@ type VerifyOTPRequest struct {
 }
 
 type CreateTeamRequest struct {
-	ContestID   uuid.UUID   `json:"contest_id" binding:"required"`
-	TeamName    string      `json:"team_name" binding:"required"`
-	PlayerIDs   []uuid.UUID `json:"player_ids" binding:"required"`
-	CaptainID   uuid.UUID   `json:"captain_id" binding:"required"`
-	ViceCaptainID uuid.UUID `json:"vice_captain_id" binding:"required"`
+	ContestID     uuid.UUID   `json:"contest_id" binding:"required"`
+	TeamName      string      `json:"team_name" binding:"required"`
+	PlayerIDs     []uuid.UUID `json:"player_ids" binding:"required"`
+	CaptainID     uuid.UUID   `json:"captain_id" binding:"required"`
+	ViceCaptainID uuid.UUID   `json:"vice_captain_id" binding:"required"`
+	SavedLineupID *uuid.UUID  `json:"-"` // Set when joining with a saved lineup
+}
+
+// UpdateTeamRequest - Swap players, captain or vice-captain before the lock
+type UpdateTeamRequest struct {
+	TeamName      string      `json:"team_name"` // Unchanged when empty
+	PlayerIDs     []uuid.UUID `json:"player_ids" binding:"required"`
+	CaptainID     uuid.UUID   `json:"captain_id" binding:"required"`
+	ViceCaptainID uuid.UUID   `json:"vice_captain_id" binding:"required"`
+}
+
+// SaveLineupRequest - Save a lineup for a match, to enter into its contests
+type SaveLineupRequest struct {
+	MatchID       uuid.UUID   `json:"match_id" binding:"required"`
+	Name          string      `json:"name" binding:"required"`
+	PlayerIDs     []uuid.UUID `json:"player_ids" binding:"required"`
+	CaptainID     uuid.UUID   `json:"captain_id" binding:"required"`
+	ViceCaptainID uuid.UUID   `json:"vice_captain_id" binding:"required"`
+}
+
+// UpdateLineupRequest - Change a saved lineup and every open entry using it
+type UpdateLineupRequest struct {
+	Name          string      `json:"name"` // Unchanged when empty
+	PlayerIDs     []uuid.UUID `json:"player_ids" binding:"required"`
+	CaptainID     uuid.UUID   `json:"captain_id" binding:"required"`
+	ViceCaptainID uuid.UUID   `json:"vice_captain_id" binding:"required"`
+}
+
+// CloneLineupRequest - Copy a saved lineup to tweak separately
+type CloneLineupRequest struct {
+	Name string `json:"name"` // Defaults to the original's name with " (copy)"
+}
+
+// JoinWithLineupRequest - Enter a saved lineup into contests on its match
+type JoinWithLineupRequest struct {
+	ContestIDs []uuid.UUID `json:"contest_ids" binding:"required,min=1"`
+}
+
+// PayAndJoinRequest - Join a contest, topping up the wallet first if it is short
+type PayAndJoinRequest struct {
+	CreateTeamRequest
+	Gateway string `json:"gateway"` // phonepe, razorpay; defaults to phonepe
 }
 
 type UpdateStatsRequest struct {
@@ -178,12 +529,50 @@ type UpdateStatsRequest struct {
 	TeamKillPenalty     int  `json:"team_kill_penalty"`
 }
 
+// PlayerStatsImportRow - One player's stats in a bulk match import sheet
+type PlayerStatsImportRow struct {
+	Row        int                `json:"row"`
+	PlayerID   uuid.UUID          `json:"player_id"`
+	Stats      UpdateStatsRequest `json:"stats"`
+	ParseError string             `json:"-"`
+}
+
+// StatsImportRowError - Why a row of a bulk import was rejected
+type StatsImportRowError struct {
+	Row      int    `json:"row"`
+	PlayerID string `json:"player_id,omitempty"`
+	Error    string `json:"error"`
+}
+
+// StatsImportResult - Outcome of a bulk stats import or dry run
+type StatsImportResult struct {
+	MatchID   uuid.UUID             `json:"match_id"`
+	DryRun    bool                  `json:"dry_run"`
+	TotalRows int                   `json:"total_rows"`
+	ValidRows int                   `json:"valid_rows"`
+	Imported  int                   `json:"imported"`
+	Errors    []StatsImportRowError `json:"errors"`
+}
+
 type CreatePaymentOrderRequest struct {
 	Amount    float64 `json:"amount" binding:"required"`
 	Currency  string  `json:"currency"`
 	ContestID uuid.UUID `json:"contest_id"`
 }
 
+// InitiateDepositRequest - User adds money to their wallet through a gateway
+type InitiateDepositRequest struct {
+	Amount    float64    `json:"amount" binding:"required,gt=0"`
+	Gateway   string     `json:"gateway"` // phonepe, razorpay; defaults to phonepe
+	ContestID *uuid.UUID `json:"contest_id,omitempty"`
+	Purpose   string     `json:"purpose"`
+}
+
+// RefundDepositRequest - Admin returns a deposit to its payment method
+type RefundDepositRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
 // === ADMIN-CONTROLLED MODELS ===
 
 // UsernamePrefix - Admin can manage username prefixes
@@ -206,6 +595,7 @@ type Game struct {
 	MinPlayersPerTeam int           `json:"min_players_per_team" gorm:"default:4"`
 	ScoringRules      string        `json:"scoring_rules" gorm:"type:jsonb"` // JSON structure for scoring
 	PlayerRoles       string        `json:"player_roles" gorm:"type:jsonb"` // JSON array of available roles
+	TeamComposition   string        `json:"team_composition" gorm:"type:jsonb;default:'{}'"` // JSON TeamComposition for fantasy teams
 	CreatedAt         time.Time     `json:"created_at"`
 	UpdatedAt         time.Time     `json:"updated_at"`
 	Tournaments       []Tournament  `json:"tournaments" gorm:"foreignKey:GameID"`
@@ -224,6 +614,58 @@ type GameScoringRule struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// ScoringRuleset - Immutable, versioned snapshot of a game's scoring rules
+type ScoringRuleset struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	GameID    uuid.UUID `json:"game_id" gorm:"uniqueIndex:idx_scoring_ruleset_game_version"`
+	Game      Game      `json:"game" gorm:"foreignKey:GameID"`
+	Version   int       `json:"version" gorm:"not null;uniqueIndex:idx_scoring_ruleset_game_version"`
+	Rules     string    `json:"rules" gorm:"type:jsonb"` // JSON array of ScoringRulesetRule
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ScoringRulesetRule - A single rule inside a ScoringRuleset snapshot
+type ScoringRulesetRule struct {
+	ActionType  string  `json:"action_type"`
+	Points      float64 `json:"points"`
+	Description string  `json:"description"`
+}
+
+// GamePlacementPoint - Points every player of a squad earns for finishing at
+// a placement in a battle-royale match
+type GamePlacementPoint struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	GameID    uuid.UUID `json:"game_id" gorm:"uniqueIndex:idx_game_placement"`
+	Game      Game      `json:"game" gorm:"foreignKey:GameID"`
+	Placement int       `json:"placement" gorm:"not null;uniqueIndex:idx_game_placement"`
+	Points    float64   `json:"points" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MatchTeam - An eSports squad playing in a match. Fantasy teams for the
+// match may only pick players from these squads.
+type MatchTeam struct {
+	ID            uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MatchID       uuid.UUID   `json:"match_id" gorm:"uniqueIndex:idx_match_team"`
+	Match         Match       `json:"-" gorm:"foreignKey:MatchID"`
+	ESportsTeamID uuid.UUID   `json:"esports_team_id" gorm:"uniqueIndex:idx_match_team"`
+	ESportsTeam   ESportsTeam `json:"esports_team" gorm:"foreignKey:ESportsTeamID"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// MatchTeamPlacement - Where an eSports squad finished in a match
+type MatchTeamPlacement struct {
+	ID            uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MatchID       uuid.UUID   `json:"match_id" gorm:"uniqueIndex:idx_match_team_placement"`
+	Match         Match       `json:"-" gorm:"foreignKey:MatchID"`
+	ESportsTeamID uuid.UUID   `json:"esports_team_id" gorm:"uniqueIndex:idx_match_team_placement"`
+	ESportsTeam   ESportsTeam `json:"esports_team" gorm:"foreignKey:ESportsTeamID"`
+	Placement     int         `json:"placement" gorm:"not null"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
 // Achievement - Gamification achievements
 type Achievement struct {
 	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -251,17 +693,19 @@ type UserAchievement struct {
 
 // ContestTemplate - Admin can create contest templates
 type ContestTemplate struct {
-	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Name           string    `json:"name" gorm:"not null"`
-	GameID         uuid.UUID `json:"game_id"`
-	Game           Game      `json:"game" gorm:"foreignKey:GameID"`
-	EntryFee       float64   `json:"entry_fee" gorm:"not null"`
-	PrizeStructure string    `json:"prize_structure" gorm:"type:jsonb"` // JSON prize distribution
-	MaxEntries     int       `json:"max_entries" gorm:"not null"`
-	IsVIP          bool      `json:"is_vip" gorm:"default:false"`
-	IsActive       bool      `json:"is_active" gorm:"default:true"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID                uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name              string    `json:"name" gorm:"not null"`
+	GameID            uuid.UUID `json:"game_id"`
+	Game              Game      `json:"game" gorm:"foreignKey:GameID"`
+	EntryFee          float64   `json:"entry_fee" gorm:"not null"`
+	PrizeStructure    string    `json:"prize_structure" gorm:"type:jsonb"` // JSON prize distribution
+	MaxEntries        int       `json:"max_entries" gorm:"not null"`
+	MinEntries        int       `json:"min_entries" gorm:"default:0"`
+	MaxEntriesPerUser int       `json:"max_entries_per_user" gorm:"default:1"`
+	IsVIP             bool      `json:"is_vip" gorm:"default:false"`
+	IsActive          bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 // PlayerAnalytics - Enhanced player analytics
@@ -315,12 +759,33 @@ type UsernameGenerationRequest struct {
 
 // CreateGameRequest - Admin creates new game
 type CreateGameRequest struct {
-	Name              string   `json:"name" binding:"required"`
-	DisplayName       string   `json:"display_name" binding:"required"`
-	Icon              string   `json:"icon"` // Base64 encoded
-	MaxPlayersPerTeam int      `json:"max_players_per_team" binding:"required"`
-	MinPlayersPerTeam int      `json:"min_players_per_team" binding:"required"`
-	PlayerRoles       []string `json:"player_roles" binding:"required"`
+	Name              string           `json:"name" binding:"required"`
+	DisplayName       string           `json:"display_name" binding:"required"`
+	Icon              string           `json:"icon"` // Base64 encoded
+	MaxPlayersPerTeam int              `json:"max_players_per_team" binding:"required"`
+	MinPlayersPerTeam int              `json:"min_players_per_team" binding:"required"`
+	PlayerRoles       []string         `json:"player_roles" binding:"required"`
+	TeamComposition   *TeamComposition `json:"team_composition"` // Leaves the current rules when omitted on update
+}
+
+// TeamComposition - Rules every fantasy team for a game must follow
+type TeamComposition struct {
+	TeamSize          int         `json:"team_size,omitempty"`            // Exact players per team; 0 allows the game's min to max players per team
+	CreditCap         float64     `json:"credit_cap,omitempty"`           // Most credits a team may spend; 0 for the default of 100
+	MaxPerESportsTeam int         `json:"max_per_esports_team,omitempty"` // Most players from one squad; 0 for no limit
+	Roles             []RoleLimit `json:"roles,omitempty"`
+}
+
+// RoleLimit - How many players of one role a team needs and may have
+type RoleLimit struct {
+	Role string `json:"role"`
+	Min  int    `json:"min"`
+	Max  int    `json:"max,omitempty"` // 0 for no limit
+}
+
+// SetMatchTeamsRequest - Admin sets the squads playing in a match
+type SetMatchTeamsRequest struct {
+	ESportsTeamIDs []uuid.UUID `json:"esports_team_ids" binding:"required,min=1"`
 }
 
 // CreateScoringRuleRequest - Admin creates scoring rules
@@ -331,6 +796,133 @@ type CreateScoringRuleRequest struct {
 	Description string    `json:"description"`
 }
 
+// PlacementPointEntry - One row of a game's placement points table
+type PlacementPointEntry struct {
+	Placement int     `json:"placement" binding:"required,min=1"`
+	Points    float64 `json:"points"`
+}
+
+// SetPlacementPointsRequest - Admin replaces a game's placement points table
+type SetPlacementPointsRequest struct {
+	Placements []PlacementPointEntry `json:"placements" binding:"required,dive"`
+}
+
+// MatchPlacementEntry - Where one squad finished in a match
+type MatchPlacementEntry struct {
+	ESportsTeamID uuid.UUID `json:"esports_team_id" binding:"required"`
+	Placement     int       `json:"placement" binding:"required,min=1"`
+}
+
+// SetMatchPlacementsRequest - Admin records squad placements for a match
+type SetMatchPlacementsRequest struct {
+	Results []MatchPlacementEntry `json:"results" binding:"required,min=1,dive"`
+}
+
+// ScoringRulesetDiffEntry - One changed action type between two ruleset versions
+type ScoringRulesetDiffEntry struct {
+	ActionType string   `json:"action_type"`
+	Change     string   `json:"change"` // added, removed, changed
+	FromPoints *float64 `json:"from_points"`
+	ToPoints   *float64 `json:"to_points"`
+}
+
+// WalletBalanceResponse - A user's wallet split into buckets
+type WalletBalanceResponse struct {
+	Balance      float64 `json:"balance"`
+	Deposit      float64 `json:"deposit"`
+	Winnings     float64 `json:"winnings"`
+	Bonus        float64 `json:"bonus"`
+	Withdrawable float64 `json:"withdrawable"` // Only winnings can be withdrawn
+}
+
+// PrizeStructure - Schema of Contest.PrizePool and ContestTemplate.PrizeStructure
+type PrizeStructure struct {
+	PoolType         string           `json:"pool_type"`                   // entry_based, guaranteed
+	GuaranteedAmount float64          `json:"guaranteed_amount,omitempty"` // Pool paid out regardless of fill
+	RakePercent      float64          `json:"rake_percent"`                // Platform share of entry fees
+	Ranks            []PrizeRankRange `json:"ranks"`
+}
+
+// PrizeRankRange - Prize paid to each rank from From to To inclusive, either
+// a fixed amount or a percentage of the prize pool
+type PrizeRankRange struct {
+	From    int     `json:"from"`
+	To      int     `json:"to"`
+	Amount  float64 `json:"amount,omitempty"`
+	Percent float64 `json:"percent,omitempty"`
+}
+
+// CreateSettlementCorrectionRequest - Admin re-settles a paid-out contest
+type CreateSettlementCorrectionRequest struct {
+	ReasonCode string `json:"reason_code" binding:"required"`
+	Reason     string `json:"reason"`
+}
+
+// RejectSettlementCorrectionRequest - Admin discards a proposed correction
+type RejectSettlementCorrectionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CancelContestRequest - Admin cancels a match or contest, refunding entries
+type CancelContestRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// CreateWithdrawalRequest - User cashes out part of their winnings
+type CreateWithdrawalRequest struct {
+	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	Method      string  `json:"method" binding:"required,oneof=upi bank_transfer"`
+	Destination string  `json:"destination" binding:"required"`
+}
+
+// RejectWithdrawalRequest - Admin turns down a withdrawal
+type RejectWithdrawalRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ScoringPreviewRequest - Admin scores a completed match with a candidate ruleset
+type ScoringPreviewRequest struct {
+	MatchID uuid.UUID            `json:"match_id" binding:"required"`
+	Rules   []ScoringRulesetRule `json:"rules" binding:"required,min=1"`
+}
+
+// PlayerPointsPreview - A player's current and candidate points for a match
+type PlayerPointsPreview struct {
+	PlayerID      uuid.UUID             `json:"player_id"`
+	PlayerName    string                `json:"player_name"`
+	CurrentPoints float64               `json:"current_points"`
+	PreviewPoints float64               `json:"preview_points"`
+	Delta         float64               `json:"delta"`
+	Items         []PointsBreakdownItem `json:"items"`
+}
+
+// FantasyTeamPreview - A fantasy team's current and candidate total and rank
+type FantasyTeamPreview struct {
+	FantasyTeamID uuid.UUID `json:"fantasy_team_id"`
+	TeamName      string    `json:"team_name"`
+	UserID        uuid.UUID `json:"user_id"`
+	CurrentPoints float64   `json:"current_points"`
+	PreviewPoints float64   `json:"preview_points"`
+	CurrentRank   int       `json:"current_rank"`
+	PreviewRank   int       `json:"preview_rank"`
+	RankChange    int       `json:"rank_change"` // positive means the team moves up
+}
+
+// ContestScoringPreview - Leaderboard of one contest under a candidate ruleset
+type ContestScoringPreview struct {
+	ContestID   uuid.UUID            `json:"contest_id"`
+	ContestName string               `json:"contest_name"`
+	TeamsMoved  int                  `json:"teams_moved"`
+	Leaderboard []FantasyTeamPreview `json:"leaderboard"`
+}
+
+// ScoringPreviewResult - Effect of a candidate ruleset on a completed match
+type ScoringPreviewResult struct {
+	MatchID  uuid.UUID               `json:"match_id"`
+	Players  []PlayerPointsPreview   `json:"players"`
+	Contests []ContestScoringPreview `json:"contests"`
+}
+
 // LiveMatchUpdateRequest - Admin updates match data live
 type LiveMatchUpdateRequest struct {
 	PlayerID            uuid.UUID `json:"player_id" binding:"required"`
@@ -339,6 +931,11 @@ type LiveMatchUpdateRequest struct {
 	Timestamp           time.Time `json:"timestamp"`
 }
 
+// CorrectMatchEventRequest - Admin reverses a match stat event
+type CorrectMatchEventRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
 // ReferralRequest - User referral
 type ReferralRequest struct {
 	ReferralCode string `json:"referral_code" binding:"required"`
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"esports-fantasy-backend/internal/models"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ContestRepository interface {
@@ -12,13 +15,13 @@ type ContestRepository interface {
 	GetContestsByMatchID(matchID uuid.UUID) ([]models.Contest, error)
 	GetContestByID(id uuid.UUID) (*models.Contest, error)
 	UpdateContest(contest *models.Contest) error
-	IncrementEntries(contestID uuid.UUID) error
 	
 	// New methods for enhanced features
 	Create(contest *models.Contest) error
 	GetByID(id string) (*models.Contest, error)
 	Update(contest *models.Contest) error
 	GetContestsByStatus(status string) ([]*models.Contest, error)
+	LockOpenContest(id uuid.UUID, rulesetID *uuid.UUID) (*models.Contest, bool, error)
 }
 
 type contestRepository struct {
@@ -52,11 +55,6 @@ func (r *contestRepository) UpdateContest(contest *models.Contest) error {
 	return r.db.Save(contest).Error
 }
 
-func (r *contestRepository) IncrementEntries(contestID uuid.UUID) error {
-	return r.db.Model(&models.Contest{}).Where("id = ?", contestID).
-		Update("current_entries", gorm.Expr("current_entries + 1")).Error
-}
-
 // New methods for enhanced features
 func (r *contestRepository) Create(contest *models.Contest) error {
 	return r.db.Create(contest).Error
@@ -75,6 +73,43 @@ func (r *contestRepository) Update(contest *models.Contest) error {
 	return r.db.Save(contest).Error
 }
 
+// LockOpenContest locks a contest that is still open, pinning rulesetID unless
+// it already has a ruleset. The row is held FOR UPDATE and only the lock
+// columns are written, so entries that joined meanwhile are kept. Reports
+// false, with the contest as found, when it is no longer open.
+func (r *contestRepository) LockOpenContest(id uuid.UUID, rulesetID *uuid.UUID) (*models.Contest, bool, error) {
+	var contest models.Contest
+	locked := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&contest, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if !strings.EqualFold(contest.Status, "open") {
+			return nil
+		}
+
+		now := time.Now()
+		if contest.ScoringRulesetID == nil {
+			contest.ScoringRulesetID = rulesetID
+		}
+		contest.Status = "LOCKED"
+		contest.LockedAt = &now
+		contest.UpdatedAt = now
+		locked = true
+
+		return tx.Model(&contest).Updates(map[string]interface{}{
+			"status":             contest.Status,
+			"locked_at":          now,
+			"scoring_ruleset_id": contest.ScoringRulesetID,
+			"updated_at":         now,
+		}).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &contest, locked, nil
+}
+
 func (r *contestRepository) GetContestsByStatus(status string) ([]*models.Contest, error) {
 	var contests []*models.Contest
 	err := r.db.Where("status = ?", status).Preload("Match").Find(&contests).Error
@@ -13,7 +13,6 @@ type FantasyTeamRepository interface {
 	GetTeamsByContestID(contestID uuid.UUID) ([]models.FantasyTeam, error)
 	GetTeamByID(id uuid.UUID) (*models.FantasyTeam, error)
 	UpdateTeam(team *models.FantasyTeam) error
-	CheckUserAlreadyInContest(userID, contestID uuid.UUID) (bool, error)
 	GetLeaderboard(contestID uuid.UUID, limit int) ([]models.FantasyTeam, error)
 }
 
@@ -68,19 +67,11 @@ func (r *fantasyTeamRepository) UpdateTeam(team *models.FantasyTeam) error {
 	return r.db.Save(team).Error
 }
 
-func (r *fantasyTeamRepository) CheckUserAlreadyInContest(userID, contestID uuid.UUID) (bool, error) {
-	var count int64
-	err := r.db.Model(&models.FantasyTeam{}).
-		Where("user_id = ? AND contest_id = ?", userID, contestID).
-		Count(&count).Error
-	return count > 0, err
-}
-
 func (r *fantasyTeamRepository) GetLeaderboard(contestID uuid.UUID, limit int) ([]models.FantasyTeam, error) {
 	var teams []models.FantasyTeam
 	query := r.db.Where("contest_id = ?", contestID).
 		Preload("User").
-		Order("total_points DESC")
+		Order("total_points DESC, created_at ASC")
 	
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -88,4 +79,4 @@ func (r *fantasyTeamRepository) GetLeaderboard(contestID uuid.UUID, limit int) (
 
 	err := query.Find(&teams).Error
 	return teams, err
-}
\ No newline at end of file
+}
@@ -17,6 +17,8 @@ type MatchRepository interface {
 	UpdateMatchStatus(id uuid.UUID, status string) error
 	GetUpcomingMatches() ([]models.Match, error)
 	GetMatchesNeedingLock() ([]models.Match, error)
+	SetMatchTeams(matchID uuid.UUID, teamIDs []uuid.UUID) error
+	GetMatchTeams(matchID uuid.UUID) ([]models.MatchTeam, error)
 	
 	// New methods for enhanced features
 	GetByID(id string) (*models.Match, error)
@@ -78,6 +80,27 @@ func (r *matchRepository) GetMatchesNeedingLock() ([]models.Match, error) {
 	return matches, err
 }
 
+// SetMatchTeams replaces the squads playing in a match
+func (r *matchRepository) SetMatchTeams(matchID uuid.UUID, teamIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("match_id = ?", matchID).Delete(&models.MatchTeam{}).Error; err != nil {
+			return err
+		}
+
+		teams := make([]models.MatchTeam, 0, len(teamIDs))
+		for _, teamID := range teamIDs {
+			teams = append(teams, models.MatchTeam{MatchID: matchID, ESportsTeamID: teamID})
+		}
+		return tx.Create(&teams).Error
+	})
+}
+
+func (r *matchRepository) GetMatchTeams(matchID uuid.UUID) ([]models.MatchTeam, error) {
+	var teams []models.MatchTeam
+	err := r.db.Preload("ESportsTeam").Where("match_id = ?", matchID).Order("created_at ASC").Find(&teams).Error
+	return teams, err
+}
+
 // New methods for enhanced features
 func (r *matchRepository) GetByID(id string) (*models.Match, error) {
 	var match models.Match
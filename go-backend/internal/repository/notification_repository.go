@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"esports-fantasy-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	GetByUserID(userID uuid.UUID, limit int) ([]models.Notification, error)
+	CountUnread(userID uuid.UUID) (int64, error)
+	MarkAsRead(id, userID uuid.UUID) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(notification *models.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+func (r *notificationRepository) GetByUserID(userID uuid.UUID, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *notificationRepository) CountUnread(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).Where("user_id = ? AND is_read = ?", userID, false).Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkAsRead(id, userID uuid.UUID) error {
+	result := r.db.Model(&models.Notification{}).Where("id = ? AND user_id = ?", id, userID).Update("is_read", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
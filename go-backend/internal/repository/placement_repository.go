@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"esports-fantasy-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PlacementRepository interface {
+	GetPointsByGameID(gameID uuid.UUID) ([]models.GamePlacementPoint, error)
+	GetByMatchID(matchID uuid.UUID) ([]models.MatchTeamPlacement, error)
+}
+
+type placementRepository struct {
+	db *gorm.DB
+}
+
+func NewPlacementRepository(db *gorm.DB) PlacementRepository {
+	return &placementRepository{db: db}
+}
+
+func (r *placementRepository) GetPointsByGameID(gameID uuid.UUID) ([]models.GamePlacementPoint, error) {
+	var points []models.GamePlacementPoint
+	err := r.db.Where("game_id = ?", gameID).Order("placement ASC").Find(&points).Error
+	return points, err
+}
+
+func (r *placementRepository) GetByMatchID(matchID uuid.UUID) ([]models.MatchTeamPlacement, error) {
+	var placements []models.MatchTeamPlacement
+	err := r.db.Preload("ESportsTeam").Where("match_id = ?", matchID).Order("placement ASC").Find(&placements).Error
+	return placements, err
+}
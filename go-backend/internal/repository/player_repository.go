@@ -67,8 +67,18 @@ func (r *playerRepository) GetPlayersByIDs(ids []uuid.UUID) ([]models.Player, er
 
 func (r *playerRepository) GetPlayersByMatchID(matchID string) ([]*models.Player, error) {
 	var players []*models.Player
-	// This query would typically involve joining through matches -> teams -> players
-	// For now, return all players as it's a simulation service
-	err := r.db.Preload("ESportsTeam").Find(&players).Error
+	query := r.db.Preload("ESportsTeam")
+
+	// Only the squads playing in the match, once they are set
+	var teams int64
+	if err := r.db.Model(&models.MatchTeam{}).Where("match_id = ?", matchID).Count(&teams).Error; err != nil {
+		return nil, err
+	}
+	if teams > 0 {
+		query = query.Where("esports_team_id IN (?)",
+			r.db.Model(&models.MatchTeam{}).Select("esports_team_id").Where("match_id = ?", matchID))
+	}
+
+	err := query.Find(&players).Error
 	return players, err
 }
\ No newline at end of file
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"esports-fantasy-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ScoringRulesetRepository interface {
+	GetByID(id uuid.UUID) (*models.ScoringRuleset, error)
+	GetByGameID(gameID uuid.UUID) ([]models.ScoringRuleset, error)
+	GetByGameIDAndVersion(gameID uuid.UUID, version int) (*models.ScoringRuleset, error)
+	GetLatestByGameID(gameID uuid.UUID) (*models.ScoringRuleset, error)
+}
+
+type scoringRulesetRepository struct {
+	db *gorm.DB
+}
+
+func NewScoringRulesetRepository(db *gorm.DB) ScoringRulesetRepository {
+	return &scoringRulesetRepository{db: db}
+}
+
+func (r *scoringRulesetRepository) GetByID(id uuid.UUID) (*models.ScoringRuleset, error) {
+	var ruleset models.ScoringRuleset
+	err := r.db.First(&ruleset, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ruleset, nil
+}
+
+func (r *scoringRulesetRepository) GetByGameID(gameID uuid.UUID) ([]models.ScoringRuleset, error) {
+	var rulesets []models.ScoringRuleset
+	err := r.db.Where("game_id = ?", gameID).Order("version DESC").Find(&rulesets).Error
+	return rulesets, err
+}
+
+func (r *scoringRulesetRepository) GetByGameIDAndVersion(gameID uuid.UUID, version int) (*models.ScoringRuleset, error) {
+	var ruleset models.ScoringRuleset
+	err := r.db.Where("game_id = ? AND version = ?", gameID, version).First(&ruleset).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ruleset, nil
+}
+
+func (r *scoringRulesetRepository) GetLatestByGameID(gameID uuid.UUID) (*models.ScoringRuleset, error) {
+	var ruleset models.ScoringRuleset
+	err := r.db.Where("game_id = ?", gameID).Order("version DESC").First(&ruleset).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ruleset, nil
+}
@@ -17,7 +17,6 @@ type UserRepository interface {
 	CreateOTP(otp *models.OTP) error
 	GetValidOTP(phoneNumber, code string) (*models.OTP, error)
 	MarkOTPAsUsed(otpID uuid.UUID) error
-	UpdateWalletBalance(userID uuid.UUID, amount float64) error
 	
 	// New methods for enhanced features
 	Create(user *models.User) error
@@ -92,11 +91,6 @@ func (r *userRepository) MarkOTPAsUsed(otpID uuid.UUID) error {
 	return r.db.Model(&models.OTP{}).Where("id = ?", otpID).Update("used", true).Error
 }
 
-func (r *userRepository) UpdateWalletBalance(userID uuid.UUID, amount float64) error {
-	return r.db.Model(&models.User{}).Where("id = ?", userID).
-		Update("wallet_balance", gorm.Expr("wallet_balance + ?", amount)).Error
-}
-
 // New methods for enhanced features
 func (r *userRepository) Create(user *models.User) error {
 	return r.db.Create(user).Error
@@ -17,11 +17,17 @@ func SetupRoutes(
 	userHandler *http.UserHandler,
 	adminHandler *http.AdminHandler,
 	contestHandler *http.ContestHandler,
+	lineupHandler *http.LineupHandler,
 	paymentHandler *http.PaymentHandler,
 	phonePeHandler *http.PhonePeHandler,
 	analyticsHandler *http.AnalyticsHandler,
 	matchSimulationHandler *http.MatchSimulationHandler,
 	autoContestHandler *http.AutoContestHandler,
+	matchFeedHandler *http.MatchFeedHandler,
+	settlementCorrectionHandler *http.SettlementCorrectionHandler,
+	notificationHandler *http.NotificationHandler,
+	ledgerHandler *http.LedgerHandler,
+	withdrawalHandler *http.WithdrawalHandler,
 	wsHandler *ws.WebSocketHandler,
 	adminEnhancedHandler *http.AdminEnhancedHandler,
 	userEnhancedHandler *http.UserEnhancedHandler,
@@ -80,6 +86,8 @@ func SetupRoutes(
 		public.GET("/contests/match/:matchId", contestHandler.GetContestsByMatch)
 		public.GET("/contests/:id", contestHandler.GetContestDetails)
 		public.GET("/contests/:id/leaderboard", contestHandler.GetLeaderboard)
+		public.GET("/matches/:matchId/players/:playerId/points", contestHandler.GetPlayerPointsBreakdown)
+		public.GET("/matches/:matchId/placements", contestHandler.GetMatchPlacements)
 		
 		// Public analytics
 		public.GET("/analytics/match/:matchId", analyticsHandler.GetMatchAnalytics)
@@ -122,13 +130,31 @@ func SetupRoutes(
 			user.POST("/season-leagues/:id/join", userAdvancedHandler.JoinSeasonLeague)
 			user.GET("/season-leagues/:id/leaderboard", userAdvancedHandler.GetSeasonLeagueLeaderboard)
 			user.GET("/player-heatmap", userAdvancedHandler.GetPlayerHeatmap)
+
+			// Notifications
+			user.GET("/notifications", notificationHandler.GetNotifications)
+			user.POST("/notifications/:id/read", notificationHandler.MarkNotificationRead)
+
+			// Withdrawals
+			user.POST("/withdrawals", withdrawalHandler.RequestWithdrawal)
+			user.GET("/withdrawals", withdrawalHandler.GetMyWithdrawals)
 		}
 
 		// Fantasy team routes
 		fantasy := protected.Group("/fantasy")
 		{
 			fantasy.POST("/teams", contestHandler.CreateFantasyTeam)
+			fantasy.POST("/teams/pay-and-join", contestHandler.PayAndJoinContest)
 			fantasy.GET("/teams", contestHandler.GetUserTeams)
+			fantasy.GET("/teams/:id/points", contestHandler.GetTeamPointsBreakdown)
+			fantasy.PUT("/teams/:id", contestHandler.UpdateFantasyTeam)
+			fantasy.GET("/teams/:id/history", contestHandler.GetTeamHistory)
+			fantasy.POST("/lineups", lineupHandler.CreateLineup)
+			fantasy.GET("/lineups", lineupHandler.GetUserLineups)
+			fantasy.GET("/lineups/:id", lineupHandler.GetLineup)
+			fantasy.PUT("/lineups/:id", lineupHandler.UpdateLineup)
+			fantasy.POST("/lineups/:id/clone", lineupHandler.CloneLineup)
+			fantasy.POST("/lineups/:id/join", lineupHandler.JoinContestsWithLineup)
 		}
 
 		// Payment routes
@@ -166,9 +192,14 @@ func SetupRoutes(
 		admin.POST("/matches", adminHandler.CreateMatch)
 		admin.GET("/matches", adminHandler.GetMatches)
 		admin.PUT("/matches/:id/status", adminHandler.UpdateMatchStatus)
+		admin.POST("/matches/:id/cancel", adminHandler.CancelMatch)
+		admin.POST("/matches/:id/events", adminHandler.RecordMatchEvent)
+		admin.GET("/matches/:id/events", adminHandler.GetMatchEvents)
+		admin.POST("/match-events/:eventId/correct", adminHandler.CorrectMatchEvent)
 
 		// Contest management
 		admin.POST("/contests", adminHandler.CreateContest)
+		admin.POST("/contests/:id/cancel", adminHandler.CancelContest)
 
 		// Player management
 		admin.POST("/players", adminHandler.CreatePlayer)
@@ -177,6 +208,12 @@ func SetupRoutes(
 
 		// Stats management
 		admin.PUT("/stats/match/:matchId/player/:playerId", adminHandler.UpdatePlayerStats)
+		admin.POST("/stats/match/:matchId/player/:playerId/rebuild", adminHandler.RebuildPlayerStats)
+		admin.POST("/stats/match/:matchId/import", adminHandler.ImportPlayerStats)
+		admin.POST("/matches/:id/rescore", adminHandler.RecalculateMatchScores)
+		admin.PUT("/matches/:id/placements", adminHandler.SetMatchPlacements)
+		admin.PUT("/matches/:id/teams", adminHandler.SetMatchTeams)
+		admin.GET("/matches/:id/teams", adminHandler.GetMatchTeams)
 
 		// User management
 		admin.PUT("/users/:userId/promote", firebaseAuthHandler.PromoteToAdmin)
@@ -198,14 +235,56 @@ func SetupRoutes(
 			simAdmin.GET("/active", matchSimulationHandler.GetActiveSimulations)
 		}
 
+		// Match data feeds
+		feeds := admin.Group("/feeds")
+		{
+			feeds.POST("/match/:matchId/replay", matchFeedHandler.StartReplay)
+			feeds.POST("/match/:matchId/stop", matchFeedHandler.StopFeed)
+			feeds.GET("/active", matchFeedHandler.GetActiveFeeds)
+		}
+
 		// Auto contest management
 		autoContest := admin.Group("/auto-contest")
 		{
 			autoContest.GET("/status", autoContestHandler.GetSchedulerStatus)
 			autoContest.POST("/contests/:contestId/distribute-prizes", autoContestHandler.ForceDistributePrizes)
+			autoContest.GET("/contests/:contestId/settlement", autoContestHandler.GetContestSettlement)
 			autoContest.POST("/contests/:contestId/lock", autoContestHandler.ForceLockContest)
 		}
 
+		// Retroactive settlement corrections
+		admin.POST("/contests/:id/settlement-corrections", settlementCorrectionHandler.ProposeCorrection)
+		admin.GET("/contests/:id/settlement-corrections", settlementCorrectionHandler.GetContestCorrections)
+		settlementCorrections := admin.Group("/settlement-corrections")
+		{
+			settlementCorrections.GET("/:id", settlementCorrectionHandler.GetCorrection)
+			settlementCorrections.POST("/:id/approve", settlementCorrectionHandler.ApproveCorrection)
+			settlementCorrections.POST("/:id/reject", settlementCorrectionHandler.RejectCorrection)
+		}
+
+		// Deposit refunds
+		admin.POST("/payments/:id/refund", paymentHandler.RefundDeposit)
+
+		// Payment reconciliation
+		admin.POST("/payments/reconcile-pending", paymentHandler.ReconcilePendingDeposits)
+		admin.GET("/payments/reconciliation", paymentHandler.GetReconciliationReport)
+		admin.POST("/payments/reconciliation", paymentHandler.GenerateReconciliationReport)
+
+		// Wallet ledger
+		ledger := admin.Group("/ledger")
+		{
+			ledger.GET("/reconcile", ledgerHandler.Reconcile)
+			ledger.GET("/users/:id", ledgerHandler.GetUserBalance)
+		}
+
+		// Withdrawal review queue
+		withdrawals := admin.Group("/withdrawals")
+		{
+			withdrawals.GET("", withdrawalHandler.GetWithdrawals)
+			withdrawals.POST("/:id/approve", withdrawalHandler.ApproveWithdrawal)
+			withdrawals.POST("/:id/reject", withdrawalHandler.RejectWithdrawal)
+		}
+
 		// Enhanced admin features
 		// Username prefix management
 		usernamePrefixes := admin.Group("/username-prefixes")
@@ -225,6 +304,8 @@ func SetupRoutes(
 			games.PUT("/:id", adminEnhancedHandler.UpdateGame)
 			games.PATCH("/:id/toggle", adminEnhancedHandler.ToggleGameStatus)
 			games.DELETE("/:id", adminEnhancedHandler.DeleteGame)
+			games.GET("/:id/placement-points", adminEnhancedHandler.GetPlacementPoints)
+			games.PUT("/:id/placement-points", adminEnhancedHandler.SetPlacementPoints)
 		}
 
 		// Scoring rules management
@@ -236,6 +317,14 @@ func SetupRoutes(
 			scoringRules.DELETE("/:id", adminEnhancedHandler.DeleteScoringRule)
 		}
 
+		// Versioned scoring rulesets
+		scoringRulesets := admin.Group("/scoring-rulesets")
+		{
+			scoringRulesets.GET("", adminEnhancedHandler.GetScoringRulesets)
+			scoringRulesets.GET("/diff", adminEnhancedHandler.DiffScoringRulesets)
+			scoringRulesets.POST("/preview", adminHandler.PreviewScoring)
+		}
+
 		// Advanced admin features
 		// Achievement management
 		achievements := admin.Group("/achievements")
@@ -3,6 +3,7 @@ package services
 import (
         "fmt"
         "log"
+        "strings"
         "time"
 
         "esports-fantasy-backend/config"
@@ -14,14 +15,17 @@ import (
 )
 
 type AutoContestService struct {
-        cfg                *config.Config
-        contestRepo        repository.ContestRepository
-        matchRepo          repository.MatchRepository
-        fantasyTeamRepo    repository.FantasyTeamRepository
-        transactionRepo    repository.TransactionRepository
-        userRepo           repository.UserRepository
-        leaderboardService LeaderboardService
-        cron               *cron.Cron
+        cfg                 *config.Config
+        contestRepo         repository.ContestRepository
+        matchRepo           repository.MatchRepository
+        fantasyTeamRepo     repository.FantasyTeamRepository
+        transactionRepo     repository.TransactionRepository
+        userRepo            repository.UserRepository
+        leaderboardService  LeaderboardService
+        gameService         GameService
+        settlementService   SettlementService
+        cancellationService ContestCancellationService
+        cron                *cron.Cron
 }
 
 func NewAutoContestService(
@@ -32,16 +36,22 @@ func NewAutoContestService(
         transactionRepo repository.TransactionRepository,
         userRepo repository.UserRepository,
         leaderboardService LeaderboardService,
+        gameService GameService,
+        settlementService SettlementService,
+        cancellationService ContestCancellationService,
 ) *AutoContestService {
         return &AutoContestService{
-                cfg:                cfg,
-                contestRepo:        contestRepo,
-                matchRepo:          matchRepo,
-                fantasyTeamRepo:    fantasyTeamRepo,
-                transactionRepo:    transactionRepo,
-                userRepo:           userRepo,
-                leaderboardService: leaderboardService,
-                cron:               cron.New(),
+                cfg:                 cfg,
+                contestRepo:         contestRepo,
+                matchRepo:           matchRepo,
+                fantasyTeamRepo:     fantasyTeamRepo,
+                transactionRepo:     transactionRepo,
+                userRepo:            userRepo,
+                leaderboardService:  leaderboardService,
+                gameService:         gameService,
+                settlementService:   settlementService,
+                cancellationService: cancellationService,
+                cron:                cron.New(),
         }
 }
 
@@ -107,13 +117,13 @@ func (s *AutoContestService) autoLockContests() {
                 // Check if contest should be locked
                 timeUntilMatch := time.Until(match.StartTime)
                 if timeUntilMatch <= lockTime {
-                        // Lock the contest
-                        contest.Status = "LOCKED"
-                        now := time.Now()
-                        contest.LockedAt = &now
-                        contest.UpdatedAt = time.Now()
+                        // Under-filled contests are called off instead of locked
+                        if s.cancelIfUnderfilled(contest) {
+                                continue
+                        }
 
-                        if err := s.contestRepo.Update(&contest); err != nil {
+                        // Lock the contest under the scoring rules in force now
+                        if err := s.lockContest(contest, match); err != nil {
                                 log.Printf("❌ Error locking contest %s: %v", contest.ID, err)
                                 continue
                         }
@@ -179,80 +189,38 @@ func (s *AutoContestService) autoPrizeDistribution() {
 }
 
 func (s *AutoContestService) distributePrizes(contest *models.Contest) error {
-        // Get final leaderboard
-        leaderboard, err := s.leaderboardService.GetContestLeaderboard(contest.ID, 100)
+        settlement, err := s.settlementService.SettleContest(contest.ID)
         if err != nil {
-                return fmt.Errorf("failed to get leaderboard: %w", err)
+                return fmt.Errorf("failed to settle contest: %w", err)
         }
 
-        if len(leaderboard) == 0 {
+        if settlement.Participants == 0 {
                 log.Printf("⚠️ No participants in contest %s", contest.ID)
-                contest.PrizesDistributed = true
-                return s.contestRepo.Update(contest)
-        }
-
-        // Parse prize distribution
-        prizeDistribution := make(map[int]float64)
-        // In a real implementation, you'd parse contest.PrizePool JSON
-        // For now, simple distribution based on total prize pool
-        totalPrizePool := float64(contest.EntryFee) * float64(len(leaderboard)) * 0.9 // 90% of entry fees
-
-        // Simple prize distribution: Winner gets 50%, 2nd gets 30%, 3rd gets 20%
-        if len(leaderboard) >= 1 {
-                prizeDistribution[1] = totalPrizePool * 0.5
-        }
-        if len(leaderboard) >= 2 {
-                prizeDistribution[2] = totalPrizePool * 0.3
-        }
-        if len(leaderboard) >= 3 {
-                prizeDistribution[3] = totalPrizePool * 0.2
-        }
-
-        // Distribute prizes
-        for rank, amount := range prizeDistribution {
-                if rank <= len(leaderboard) {
-                        entry := leaderboard[rank-1]
-                        
-                        // Create prize transaction
-                        transaction := &models.Transaction{
-                                UserID:         entry.UserID,
-                                Amount:         amount,
-                                Type:           "winnings",
-                                Status:         "completed",
-                                RelatedEntityID: &contest.ID,
-                                CreatedAt:      time.Now(),
-                                UpdatedAt:      time.Now(),
-                        }
-
-                        if err := s.transactionRepo.CreateTransaction(transaction); err != nil {
-                                log.Printf("❌ Error creating prize transaction: %v", err)
-                                continue
-                        }
+        }
 
-                        // Update user wallet
-                        user, err := s.userRepo.GetByID(entry.UserID.String())
-                        if err != nil {
-                                log.Printf("❌ Error fetching user %s: %v", entry.UserID, err)
-                                continue
-                        }
+        contest.PrizesDistributed = true
+        return nil
+}
 
-                        user.WalletBalance += amount
-                        user.UpdatedAt = time.Now()
+// GetContestSettlement returns the settlement record and payouts of a contest
+func (s *AutoContestService) GetContestSettlement(contestID string) (*models.ContestSettlement, error) {
+        id, err := uuid.Parse(contestID)
+        if err != nil {
+                return nil, fmt.Errorf("invalid contest ID: %w", err)
+        }
 
-                        if err := s.userRepo.Update(user); err != nil {
-                                log.Printf("❌ Error updating user wallet: %v", err)
-                                continue
-                        }
+        return s.settlementService.GetContestSettlement(id)
+}
 
-                        log.Printf("💰 Prize distributed: ₹%.2f to user %s (Rank %d)", 
-                                amount, user.PhoneNumber, rank)
-                }
+// contestPrizesByRank returns the prize for each paid rank of a contest with
+// the given number of participants, as set out by its prize structure
+func contestPrizesByRank(contest *models.Contest, participants int) (map[int]float64, error) {
+        engine, err := NewPrizeEngine(contest.PrizePool)
+        if err != nil {
+                return nil, fmt.Errorf("contest %s: %w", contest.ID, err)
         }
 
-        // Mark contest as prizes distributed
-        contest.PrizesDistributed = true
-        contest.UpdatedAt = time.Now()
-        return s.contestRepo.Update(contest)
+        return engine.Distribute(contest.EntryFee, participants), nil
 }
 
 func (s *AutoContestService) autoUpdateMatchStatus() {
@@ -349,12 +317,71 @@ func (s *AutoContestService) ForceLockContest(contestID string) error {
                 return fmt.Errorf("contest not found: %w", err)
         }
 
-        contest.Status = "LOCKED"
-        now := time.Now()
-        contest.LockedAt = &now
-        contest.UpdatedAt = time.Now()
+        match, err := s.matchRepo.GetByID(contest.MatchID.String())
+        if err != nil {
+                return fmt.Errorf("match not found: %w", err)
+        }
+
+        return s.lockContest(contest, match)
+}
 
-        return s.contestRepo.Update(contest)
+// lockContest pins the scoring ruleset and locks the contest, provided it is
+// still open when its row is locked
+func (s *AutoContestService) lockContest(contest *models.Contest, match *models.Match) error {
+        if !strings.EqualFold(contest.Status, ContestStatusOpen) {
+                return fmt.Errorf("%w: %s", ErrContestNotOpen, contest.Status)
+        }
+
+        if err := s.pinScoringRuleset(contest, match); err != nil {
+                return fmt.Errorf("failed to pin scoring ruleset: %w", err)
+        }
+
+        locked, ok, err := s.contestRepo.LockOpenContest(contest.ID, contest.ScoringRulesetID)
+        if err != nil {
+                return fmt.Errorf("failed to lock contest: %w", err)
+        }
+        if !ok {
+                return fmt.Errorf("%w: %s", ErrContestNotOpen, locked.Status)
+        }
+
+        *contest = *locked
+        return nil
+}
+
+// cancelIfUnderfilled cancels and refunds a contest that missed its minimum
+// fill, reporting whether it did. A forced lock skips this check.
+func (s *AutoContestService) cancelIfUnderfilled(contest *models.Contest) bool {
+        if contest.MinEntries <= 0 || contest.CurrentEntries >= contest.MinEntries {
+                return false
+        }
+
+        reason := fmt.Sprintf("only %d of the %d entries needed joined before the lock", contest.CurrentEntries, contest.MinEntries)
+        if _, err := s.cancellationService.CancelContest(contest.ID, reason); err != nil {
+                log.Printf("❌ Error cancelling under-filled contest %s: %v", contest.ID, err)
+        } else {
+                log.Printf("🚫 Contest cancelled: %s (%d/%d entries)", contest.Name, contest.CurrentEntries, contest.MinEntries)
+        }
+        return true
+}
+
+// pinScoringRuleset snapshots the game's active scoring ruleset onto the
+// contest so it is always rescored with the rules it locked under
+func (s *AutoContestService) pinScoringRuleset(contest *models.Contest, match *models.Match) error {
+        if contest.ScoringRulesetID != nil || match.Tournament.GameID == uuid.Nil {
+                return nil
+        }
+
+        ruleset, err := s.gameService.GetActiveScoringRuleset(match.Tournament.GameID)
+        if err != nil {
+                return err
+        }
+
+        // Games without scoring rules keep using the default points table
+        if ruleset != nil {
+                contest.ScoringRulesetID = &ruleset.ID
+        }
+
+        return nil
 }
 
 func (s *AutoContestService) GetSchedulerStatus() map[string]interface{} {
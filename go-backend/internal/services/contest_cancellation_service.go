@@ -0,0 +1,278 @@
+package services
+
+import (
+	"errors"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Statuses set when a match or contest is called off
+const (
+	MatchStatusCancelled   = "cancelled"
+	ContestStatusCancelled = "cancelled"
+)
+
+// TransactionTypeContestRefund is the wallet transaction returning one entry fee
+const TransactionTypeContestRefund = "contest_refund"
+
+var (
+	ErrMatchNotCancellable   = errors.New("completed matches cannot be cancelled")
+	ErrContestNotCancellable = errors.New("contest has already been settled and cannot be cancelled")
+)
+
+// ContestRefundResult - What cancelling one contest refunded
+type ContestRefundResult struct {
+	ContestID       uuid.UUID `json:"contest_id"`
+	Name            string    `json:"name"`
+	Reason          string    `json:"reason"`
+	Refunded        int       `json:"refunded"`         // Entry fees refunded by this run
+	AlreadyRefunded int       `json:"already_refunded"` // Entry fees an earlier run refunded
+	Amount          float64   `json:"amount"`           // Total refunded by this run
+}
+
+// MatchCancellationResult - Contests cancelled along with a match
+type MatchCancellationResult struct {
+	MatchID  uuid.UUID             `json:"match_id"`
+	Contests []ContestRefundResult `json:"contests"`
+}
+
+// ContestCancellationService calls off contests and returns every entry fee
+// to the buckets that paid it. Cancelling again retries refunds that did not
+// go through and never refunds an entry twice.
+type ContestCancellationService interface {
+	CancelMatch(matchID uuid.UUID, reason string) (*MatchCancellationResult, error)
+	CancelContest(contestID uuid.UUID, reason string) (*ContestRefundResult, error)
+}
+
+type contestCancellationService struct {
+	db                  *gorm.DB
+	ledgerService       LedgerService
+	notificationService NotificationService
+}
+
+func NewContestCancellationService(db *gorm.DB, ledgerService LedgerService, notificationService NotificationService) ContestCancellationService {
+	return &contestCancellationService{
+		db:                  db,
+		ledgerService:       ledgerService,
+		notificationService: notificationService,
+	}
+}
+
+// contestRefundKey identifies the refund of one entry fee journal entry
+func contestRefundKey(entryID uuid.UUID) string {
+	return fmt.Sprintf("contest_refund:%s", entryID)
+}
+
+func (s *contestCancellationService) CancelMatch(matchID uuid.UUID, reason string) (*MatchCancellationResult, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var match models.Match
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&match, "id = ?", matchID).Error
+		if err != nil {
+			return fmt.Errorf("match not found: %w", err)
+		}
+		if strings.EqualFold(match.Status, "completed") {
+			return ErrMatchNotCancellable
+		}
+
+		return tx.Model(&match).Update("status", MatchStatusCancelled).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var contests []models.Contest
+	if err := s.db.Where("match_id = ?", matchID).Find(&contests).Error; err != nil {
+		return nil, fmt.Errorf("failed to get contests: %w", err)
+	}
+
+	result := &MatchCancellationResult{MatchID: matchID, Contests: []ContestRefundResult{}}
+	for _, contest := range contests {
+		refund, err := s.CancelContest(contest.ID, reason)
+		if err != nil {
+			return result, fmt.Errorf("failed to cancel contest %s: %w", contest.ID, err)
+		}
+		result.Contests = append(result.Contests, *refund)
+	}
+
+	log.Printf("🚫 Match cancelled: %s (%d contests)", matchID, len(result.Contests))
+	return result, nil
+}
+
+func (s *contestCancellationService) CancelContest(contestID uuid.UUID, reason string) (*ContestRefundResult, error) {
+	var contest models.Contest
+	newlyCancelled := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Serialises cancellation with settlement of the same contest
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&contest, "id = ?", contestID).Error
+		if err != nil {
+			return fmt.Errorf("contest not found: %w", err)
+		}
+		if strings.EqualFold(contest.Status, ContestStatusCancelled) {
+			return nil
+		}
+
+		var settlements int64
+		if err := tx.Model(&models.ContestSettlement{}).Where("contest_id = ?", contestID).Count(&settlements).Error; err != nil {
+			return fmt.Errorf("failed to check settlement: %w", err)
+		}
+		if settlements > 0 || contest.PrizesDistributed {
+			return ErrContestNotCancellable
+		}
+
+		now := time.Now()
+		contest.Status = ContestStatusCancelled
+		contest.CancelledAt = &now
+		contest.CancellationReason = reason
+		newlyCancelled = true
+		return tx.Model(&contest).Updates(map[string]interface{}{
+			"status":              contest.Status,
+			"cancelled_at":        contest.CancelledAt,
+			"cancellation_reason": contest.CancellationReason,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.JournalEntry
+	err = s.db.Preload("Lines.Account").
+		Where("kind = ? AND reference_id = ?", JournalKindContestEntry, contestID).
+		Order("created_at ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entry fees: %w", err)
+	}
+
+	result := &ContestRefundResult{
+		ContestID: contestID,
+		Name:      contest.Name,
+		Reason:    contest.CancellationReason,
+	}
+	refundedByUser := make(map[uuid.UUID]float64)
+	for i := range entries {
+		refund, err := s.refundEntry(&contest, &entries[i])
+		if err != nil {
+			return result, fmt.Errorf("failed to refund entry %s: %w", entries[i].ID, err)
+		}
+		if refund == nil {
+			result.AlreadyRefunded++
+			continue
+		}
+		result.Refunded++
+		result.Amount = roundCurrency(result.Amount + refund.Amount)
+		refundedByUser[refund.UserID] = roundCurrency(refundedByUser[refund.UserID] + refund.Amount)
+	}
+
+	s.notifyUsers(&contest, newlyCancelled, refundedByUser)
+
+	log.Printf("🚫 Contest cancelled: %s (%s) - %d entries refunded, ₹%.2f", contest.Name, contestID, result.Refunded, result.Amount)
+	return result, nil
+}
+
+// refundEntry reverses one entry fee, crediting each bucket what it paid. It
+// returns nil if the entry was refunded before.
+func (s *contestCancellationService) refundEntry(contest *models.Contest, entry *models.JournalEntry) (*models.Transaction, error) {
+	var split WalletBuckets
+	var userID uuid.UUID
+	for _, line := range entry.Lines {
+		bucket, ok := accountBucket(line.Account.Type)
+		if !ok || line.Debit <= 0 || line.Account.UserID == nil {
+			continue
+		}
+		split.add(bucket, line.Debit)
+		userID = *line.Account.UserID
+	}
+	if split.Total() <= 0 {
+		return nil, nil
+	}
+
+	key := contestRefundKey(entry.ID)
+	transaction := &models.Transaction{
+		ID:              uuid.New(),
+		UserID:          userID,
+		Amount:          split.Total(),
+		Type:            TransactionTypeContestRefund,
+		Status:          "completed",
+		RelatedEntityID: &contest.ID,
+		Description:     fmt.Sprintf("Refund for cancelled contest %s", contest.Name),
+		IdempotencyKey:  &key,
+	}
+	split.ApplyTo(transaction)
+
+	refunded := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(transaction)
+		if result.Error != nil {
+			return fmt.Errorf("failed to record refund: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		postings := []LedgerPosting{Debit(PrizeEscrowAccount(), split.Total())}
+		for _, bucket := range []string{WalletBucketDeposit, WalletBucketWinnings, WalletBucketBonus} {
+			if amount := split.get(bucket); amount > 0 {
+				postings = append(postings, Credit(userBucketAccount(bucket, userID), amount))
+			}
+		}
+
+		_, err := s.ledgerService.Post(tx, &JournalEntryRequest{
+			Kind:           JournalKindContestRefund,
+			ReferenceID:    &contest.ID,
+			TransactionID:  &transaction.ID,
+			IdempotencyKey: key,
+			Description:    "Entry fee refund",
+			Postings:       postings,
+		})
+		if err != nil {
+			return err
+		}
+		refunded = true
+		return nil
+	})
+	if err != nil || !refunded {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// notifyUsers tells players their contest was called off. Free contests have
+// no refunds, so everyone with a team hears about it once, on cancellation.
+func (s *contestCancellationService) notifyUsers(contest *models.Contest, newlyCancelled bool, refundedByUser map[uuid.UUID]float64) {
+	userIDs := make(map[uuid.UUID]bool)
+	for userID := range refundedByUser {
+		userIDs[userID] = true
+	}
+	if newlyCancelled {
+		var teamUserIDs []uuid.UUID
+		if err := s.db.Model(&models.FantasyTeam{}).Where("contest_id = ?", contest.ID).Distinct().Pluck("user_id", &teamUserIDs).Error; err != nil {
+			log.Printf("Error getting players of cancelled contest %s: %v", contest.ID, err)
+		}
+		for _, userID := range teamUserIDs {
+			userIDs[userID] = true
+		}
+	}
+
+	for userID := range userIDs {
+		message := fmt.Sprintf("%s was cancelled: %s", contest.Name, contest.CancellationReason)
+		if amount := refundedByUser[userID]; amount > 0 {
+			message = fmt.Sprintf("%s was cancelled: %s. Your entry fee of ₹%.2f has been refunded to your wallet.",
+				contest.Name, contest.CancellationReason, amount)
+		}
+
+		data := map[string]interface{}{
+			"contest_id": contest.ID,
+			"refund":     refundedByUser[userID],
+		}
+		if err := s.notificationService.Notify(userID, "contest_cancelled", "Contest cancelled", message, data); err != nil {
+			log.Printf("Error notifying user %s of contest cancellation: %v", userID, err)
+		}
+	}
+}
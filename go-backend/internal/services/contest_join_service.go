@@ -0,0 +1,313 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ContestStatusOpen is the status of a contest that can still be joined
+const ContestStatusOpen = "open"
+
+// TransactionTypeContestEntry is the wallet transaction paying one entry fee
+const TransactionTypeContestEntry = "contest_entry"
+
+// Pending contest join statuses
+const (
+	PendingJoinStatusPending = "pending"
+	PendingJoinStatusJoined  = "joined"
+	PendingJoinStatusFailed  = "failed"
+)
+
+var (
+	ErrContestNotOpen    = errors.New("contest is not open for entries")
+	ErrContestFull       = errors.New("contest is full")
+	ErrEntryLimitReached = errors.New("user has reached the entry limit for this contest")
+)
+
+// PayAndJoinResult - Either the entry, when the wallet could already pay, or
+// the deposit to complete before the join goes through
+type PayAndJoinResult struct {
+	Entry       *models.ContestEntry       `json:"entry,omitempty"`
+	PendingJoin *models.PendingContestJoin `json:"pending_join,omitempty"`
+	Payment     *PaymentSession            `json:"payment,omitempty"`
+	Shortfall   float64                    `json:"shortfall"`
+}
+
+// ContestJoinService joins users to contests. Checking the contest, paying
+// the entry fee, saving the team and taking the place all happen in one
+// transaction under a lock on the contest row, so a contest never overfills
+// and no one pays without getting an entry.
+type ContestJoinService interface {
+	JoinContest(userID uuid.UUID, req *models.CreateTeamRequest) (*models.ContestEntry, error)
+	PayAndJoin(userID uuid.UUID, req *models.PayAndJoinRequest) (*PayAndJoinResult, error)
+	DepositCompleted(deposit *models.Transaction)
+}
+
+type contestJoinService struct {
+	db                  *gorm.DB
+	fantasyTeamService  FantasyTeamService
+	ledgerService       LedgerService
+	depositService      DepositService
+	notificationService NotificationService
+}
+
+func NewContestJoinService(
+	db *gorm.DB,
+	fantasyTeamService FantasyTeamService,
+	ledgerService LedgerService,
+	depositService DepositService,
+	notificationService NotificationService,
+) ContestJoinService {
+	return &contestJoinService{
+		db:                  db,
+		fantasyTeamService:  fantasyTeamService,
+		ledgerService:       ledgerService,
+		depositService:      depositService,
+		notificationService: notificationService,
+	}
+}
+
+// contestEntryKey makes the entry fee for one entry post once
+func contestEntryKey(entryID uuid.UUID) string {
+	return fmt.Sprintf("contest_entry:%s", entryID)
+}
+
+func (s *contestJoinService) JoinContest(userID uuid.UUID, req *models.CreateTeamRequest) (*models.ContestEntry, error) {
+	var entry *models.ContestEntry
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		entry, err = s.join(tx, userID, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🎟️ User %s joined contest %s (₹%.2f)", userID, req.ContestID, entry.EntryFee)
+	return entry, nil
+}
+
+// join takes a place in the contest for a new team, paying its entry fee
+func (s *contestJoinService) join(tx *gorm.DB, userID uuid.UUID, req *models.CreateTeamRequest) (*models.ContestEntry, error) {
+	var contest models.Contest
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&contest, "id = ?", req.ContestID).Error
+	if err != nil {
+		return nil, fmt.Errorf("contest not found: %w", err)
+	}
+	if !strings.EqualFold(contest.Status, ContestStatusOpen) {
+		return nil, ErrContestNotOpen
+	}
+	if contest.CurrentEntries >= contest.MaxEntries {
+		return nil, ErrContestFull
+	}
+
+	team, err := s.fantasyTeamService.CreateFantasyTeam(tx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.ContestEntry{
+		ID:            uuid.New(),
+		ContestID:     contest.ID,
+		UserID:        userID,
+		FantasyTeamID: team.ID,
+		EntryFee:      contest.EntryFee,
+	}
+
+	if contest.EntryFee > 0 {
+		transaction := &models.Transaction{
+			ID:              uuid.New(),
+			UserID:          userID,
+			Amount:          contest.EntryFee,
+			Type:            TransactionTypeContestEntry,
+			Status:          "completed",
+			RelatedEntityID: &contest.ID,
+			Description:     fmt.Sprintf("Entry fee for %s", contest.Name),
+		}
+
+		split, err := s.ledgerService.RecordEntryFee(tx, JournalKindContestEntry, userID, contest.EntryFee, contest.ID,
+			&transaction.ID, contestEntryKey(entry.ID))
+		if err != nil {
+			return nil, err
+		}
+
+		// Record which buckets paid the fee
+		split.ApplyTo(transaction)
+		if err := tx.Create(transaction).Error; err != nil {
+			return nil, fmt.Errorf("failed to create contest entry transaction: %w", err)
+		}
+		entry.TransactionID = &transaction.ID
+	}
+
+	if err := tx.Create(entry).Error; err != nil {
+		return nil, fmt.Errorf("failed to create contest entry: %w", err)
+	}
+
+	err = tx.Model(&contest).Update("current_entries", gorm.Expr("current_entries + 1")).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to join contest: %w", err)
+	}
+
+	entry.FantasyTeam = *team
+	return entry, nil
+}
+
+// PayAndJoin joins straight away if the wallet can pay the entry fee.
+// Otherwise it starts a deposit for the shortfall and joins once the
+// deposit completes; if the join then fails, the money stays in the wallet.
+func (s *contestJoinService) PayAndJoin(userID uuid.UUID, req *models.PayAndJoinRequest) (*PayAndJoinResult, error) {
+	if err := s.fantasyTeamService.ValidateTeamCreation(&req.CreateTeamRequest); err != nil {
+		return nil, err
+	}
+
+	var contest models.Contest
+	if err := s.db.First(&contest, "id = ?", req.ContestID).Error; err != nil {
+		return nil, fmt.Errorf("contest not found: %w", err)
+	}
+	if !strings.EqualFold(contest.Status, ContestStatusOpen) {
+		return nil, ErrContestNotOpen
+	}
+	if contest.CurrentEntries >= contest.MaxEntries {
+		return nil, ErrContestFull
+	}
+
+	// Catch a user out of entries before they pay, not after
+	var entries int64
+	err := s.db.Model(&models.FantasyTeam{}).Where("user_id = ? AND contest_id = ?", userID, contest.ID).Count(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing entries: %w", err)
+	}
+	if int(entries) >= contest.MaxEntriesPerUser {
+		return nil, ErrEntryLimitReached
+	}
+
+	shortfall, err := s.ledgerService.EntryFeeShortfall(userID, contest.EntryFee)
+	if err != nil {
+		return nil, err
+	}
+	if shortfall <= 0 {
+		entry, err := s.JoinContest(userID, &req.CreateTeamRequest)
+		if err != nil {
+			return nil, err
+		}
+		return &PayAndJoinResult{Entry: entry}, nil
+	}
+
+	teamRequest, err := json.Marshal(req.CreateTeamRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode team: %w", err)
+	}
+
+	session, err := s.depositService.InitiateDeposit(userID, &models.InitiateDepositRequest{
+		Amount:    shortfall,
+		Gateway:   req.Gateway,
+		ContestID: &contest.ID,
+		Purpose:   TransactionTypeContestEntry,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pending := &models.PendingContestJoin{
+		UserID:               userID,
+		ContestID:            contest.ID,
+		DepositTransactionID: session.TransactionID,
+		TeamRequest:          string(teamRequest),
+		Status:               PendingJoinStatusPending,
+	}
+	if err := s.db.Create(pending).Error; err != nil {
+		return nil, fmt.Errorf("failed to save pending join: %w", err)
+	}
+
+	// The deposit may have completed before the pending join was saved
+	var deposit models.Transaction
+	if err := s.db.First(&deposit, "id = ?", session.TransactionID).Error; err == nil && deposit.Status == DepositStatusCompleted {
+		s.DepositCompleted(&deposit)
+		s.db.First(pending, "id = ?", pending.ID)
+	}
+
+	return &PayAndJoinResult{
+		PendingJoin: pending,
+		Payment:     session,
+		Shortfall:   shortfall,
+	}, nil
+}
+
+// DepositCompleted finishes the join a deposit was paying for, if any
+func (s *contestJoinService) DepositCompleted(deposit *models.Transaction) {
+	var pending models.PendingContestJoin
+	var entry *models.ContestEntry
+	var joinErr error
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&pending, "deposit_transaction_id = ?", deposit.ID).Error
+		if err != nil {
+			return err
+		}
+		if pending.Status != PendingJoinStatusPending {
+			return nil
+		}
+
+		var req models.CreateTeamRequest
+		if err := json.Unmarshal([]byte(pending.TeamRequest), &req); err != nil {
+			joinErr = fmt.Errorf("failed to decode team: %w", err)
+			return nil
+		}
+
+		// A failed join must not undo the pending join's status change
+		joinErr = tx.Transaction(func(tx *gorm.DB) error {
+			var err error
+			entry, err = s.join(tx, pending.UserID, &req)
+			return err
+		})
+
+		updates := map[string]interface{}{"status": PendingJoinStatusJoined}
+		if joinErr != nil {
+			updates = map[string]interface{}{
+				"status":         PendingJoinStatusFailed,
+				"failure_reason": joinErr.Error(),
+			}
+		} else {
+			updates["contest_entry_id"] = entry.ID
+		}
+		return tx.Model(&pending).Updates(updates).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("Error completing pending join for deposit %s: %v", deposit.ID, err)
+		return
+	}
+
+	switch {
+	case joinErr != nil:
+		log.Printf("⚠️ Pending join %s failed: %v", pending.ID, joinErr)
+		s.notify(pending.UserID, "Could not join contest",
+			fmt.Sprintf("Your payment of ₹%.2f was added to your wallet, but we could not join the contest: %v", deposit.Amount, joinErr),
+			&pending)
+	case entry != nil:
+		log.Printf("🎟️ User %s joined contest %s after deposit %s", pending.UserID, pending.ContestID, deposit.ID)
+		s.notify(pending.UserID, "Contest joined",
+			fmt.Sprintf("Your payment went through and %s has joined the contest.", entry.FantasyTeam.TeamName),
+			&pending)
+	}
+}
+
+func (s *contestJoinService) notify(userID uuid.UUID, title, message string, pending *models.PendingContestJoin) {
+	data := map[string]interface{}{
+		"contest_id":      pending.ContestID,
+		"pending_join_id": pending.ID,
+	}
+	if err := s.notificationService.Notify(userID, "contest_join", title, message, data); err != nil {
+		log.Printf("Error notifying user %s of contest join: %v", userID, err)
+	}
+}
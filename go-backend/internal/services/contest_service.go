@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"esports-fantasy-backend/internal/models"
 	"esports-fantasy-backend/internal/repository"
 	"fmt"
@@ -8,16 +9,22 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrInvalidMinEntries is returned for a minimum fill outside 0..max entries
+	ErrInvalidMinEntries = errors.New("min entries must be between 0 and max entries")
+	// ErrInvalidMaxEntriesPerUser is returned for a per-user limit outside 1..max entries
+	ErrInvalidMaxEntriesPerUser = errors.New("max entries per user must be between 1 and max entries")
+)
+
 type ContestService interface {
 	CreateContest(contest *models.Contest) error
 	GetContestsByMatch(matchID uuid.UUID) ([]models.Contest, error)
 	GetContestByID(id uuid.UUID) (*models.Contest, error)
-	JoinContest(userID, contestID uuid.UUID) error
 }
 
+// contestService manages contest listings; joining is ContestJoinService
 type contestService struct {
 	contestRepo repository.ContestRepository
-	userRepo    repository.UserRepository
 }
 
 func NewContestService(contestRepo repository.ContestRepository) ContestService {
@@ -27,12 +34,46 @@ func NewContestService(contestRepo repository.ContestRepository) ContestService
 }
 
 func (s *contestService) CreateContest(contest *models.Contest) error {
+	if err := validateMinEntries(contest.MinEntries, contest.MaxEntries); err != nil {
+		return err
+	}
+	maxEntriesPerUser, err := validateMaxEntriesPerUser(contest.MaxEntriesPerUser, contest.MaxEntries)
+	if err != nil {
+		return err
+	}
+	contest.MaxEntriesPerUser = maxEntriesPerUser
+
+	prizePool, err := validatePrizePool(contest.PrizePool, contest.EntryFee, contest.MaxEntries)
+	if err != nil {
+		return err
+	}
+	contest.PrizePool = prizePool
+
 	if err := s.contestRepo.CreateContest(contest); err != nil {
 		return fmt.Errorf("failed to create contest: %w", err)
 	}
 	return nil
 }
 
+func validateMinEntries(minEntries, maxEntries int) error {
+	if minEntries < 0 || minEntries > maxEntries {
+		return ErrInvalidMinEntries
+	}
+	return nil
+}
+
+// validateMaxEntriesPerUser checks the per-user limit, defaulting an unset
+// limit to one entry
+func validateMaxEntriesPerUser(maxEntriesPerUser, maxEntries int) (int, error) {
+	if maxEntriesPerUser == 0 {
+		maxEntriesPerUser = 1
+	}
+	if maxEntriesPerUser < 1 || maxEntriesPerUser > maxEntries {
+		return 0, ErrInvalidMaxEntriesPerUser
+	}
+	return maxEntriesPerUser, nil
+}
+
 func (s *contestService) GetContestsByMatch(matchID uuid.UUID) ([]models.Contest, error) {
 	contests, err := s.contestRepo.GetContestsByMatchID(matchID)
 	if err != nil {
@@ -48,23 +89,3 @@ func (s *contestService) GetContestByID(id uuid.UUID) (*models.Contest, error) {
 	}
 	return contest, nil
 }
-
-func (s *contestService) JoinContest(userID, contestID uuid.UUID) error {
-	// Get contest details
-	contest, err := s.contestRepo.GetContestByID(contestID)
-	if err != nil {
-		return fmt.Errorf("contest not found: %w", err)
-	}
-
-	// Check if contest is full
-	if contest.CurrentEntries >= contest.MaxEntries {
-		return fmt.Errorf("contest is full")
-	}
-
-	// Increment contest entries
-	if err := s.contestRepo.IncrementEntries(contestID); err != nil {
-		return fmt.Errorf("failed to join contest: %w", err)
-	}
-
-	return nil
-}
\ No newline at end of file
@@ -24,12 +24,14 @@ type ContestTemplateService interface {
 }
 
 type CreateContestTemplateRequest struct {
-	Name           string                 `json:"name" binding:"required"`
-	GameID         uuid.UUID              `json:"game_id" binding:"required"`
-	EntryFee       float64                `json:"entry_fee" binding:"required"`
-	PrizeStructure map[string]interface{} `json:"prize_structure" binding:"required"`
-	MaxEntries     int                    `json:"max_entries" binding:"required"`
-	IsVIP          bool                   `json:"is_vip"`
+	Name              string                 `json:"name" binding:"required"`
+	GameID            uuid.UUID              `json:"game_id" binding:"required"`
+	EntryFee          float64                `json:"entry_fee" binding:"required"`
+	PrizeStructure    map[string]interface{} `json:"prize_structure" binding:"required"`
+	MaxEntries        int                    `json:"max_entries" binding:"required"`
+	MinEntries        int                    `json:"min_entries"`          // Contests below this fill at lock time are cancelled
+	MaxEntriesPerUser int                    `json:"max_entries_per_user"` // Teams one user may enter; defaults to 1
+	IsVIP             bool                   `json:"is_vip"`
 }
 
 type contestTemplateService struct {
@@ -66,14 +68,28 @@ func (s *contestTemplateService) CreateTemplate(req *CreateContestTemplateReques
 		return nil, fmt.Errorf("failed to serialize prize structure: %w", err)
 	}
 
+	prizeStructure, err := validatePrizePool(string(prizeJSON), req.EntryFee, req.MaxEntries)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMinEntries(req.MinEntries, req.MaxEntries); err != nil {
+		return nil, err
+	}
+	maxEntriesPerUser, err := validateMaxEntriesPerUser(req.MaxEntriesPerUser, req.MaxEntries)
+	if err != nil {
+		return nil, err
+	}
+
 	template := &models.ContestTemplate{
-		Name:           req.Name,
-		GameID:         req.GameID,
-		EntryFee:       req.EntryFee,
-		PrizeStructure: string(prizeJSON),
-		MaxEntries:     req.MaxEntries,
-		IsVIP:          req.IsVIP,
-		IsActive:       true,
+		Name:              req.Name,
+		GameID:            req.GameID,
+		EntryFee:          req.EntryFee,
+		PrizeStructure:    prizeStructure,
+		MaxEntries:        req.MaxEntries,
+		MinEntries:        req.MinEntries,
+		MaxEntriesPerUser: maxEntriesPerUser,
+		IsVIP:             req.IsVIP,
+		IsActive:          true,
 	}
 
 	if err := s.templateRepo.Create(template); err != nil {
@@ -123,11 +139,25 @@ func (s *contestTemplateService) UpdateTemplate(id uuid.UUID, req *CreateContest
 		return fmt.Errorf("failed to serialize prize structure: %w", err)
 	}
 
+	prizeStructure, err := validatePrizePool(string(prizeJSON), req.EntryFee, req.MaxEntries)
+	if err != nil {
+		return err
+	}
+	if err := validateMinEntries(req.MinEntries, req.MaxEntries); err != nil {
+		return err
+	}
+	maxEntriesPerUser, err := validateMaxEntriesPerUser(req.MaxEntriesPerUser, req.MaxEntries)
+	if err != nil {
+		return err
+	}
+
 	template.Name = req.Name
 	template.GameID = req.GameID
 	template.EntryFee = req.EntryFee
-	template.PrizeStructure = string(prizeJSON)
+	template.PrizeStructure = prizeStructure
 	template.MaxEntries = req.MaxEntries
+	template.MinEntries = req.MinEntries
+	template.MaxEntriesPerUser = maxEntriesPerUser
 	template.IsVIP = req.IsVIP
 
 	if err := s.templateRepo.Update(template); err != nil {
@@ -165,14 +195,16 @@ func (s *contestTemplateService) CreateContestFromTemplate(templateID, matchID u
 
 	// Create contest from template
 	contest := &models.Contest{
-		MatchID:        matchID,
-		Name:           template.Name,
-		EntryFee:       template.EntryFee,
-		PrizePool:      template.PrizeStructure,
-		MaxEntries:     template.MaxEntries,
-		CurrentEntries: 0,
-		IsPrivate:      template.IsVIP, // VIP templates create private contests
-		Status:         "open",
+		MatchID:           matchID,
+		Name:              template.Name,
+		EntryFee:          template.EntryFee,
+		PrizePool:         template.PrizeStructure,
+		MaxEntries:        template.MaxEntries,
+		MinEntries:        template.MinEntries,
+		MaxEntriesPerUser: template.MaxEntriesPerUser,
+		CurrentEntries:    0,
+		IsPrivate:         template.IsVIP, // VIP templates create private contests
+		Status:            "open",
 	}
 
 	if err := s.contestRepo.Create(contest); err != nil {
@@ -0,0 +1,421 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"esports-fantasy-backend/config"
+	"esports-fantasy-backend/internal/models"
+	"esports-fantasy-backend/internal/repository"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Transaction types for gateway deposits
+const (
+	TransactionTypeDeposit       = "deposit"
+	TransactionTypeDepositRefund = "deposit_refund"
+)
+
+// gatewayTimeout bounds a single call to a payment gateway
+const gatewayTimeout = 30 * time.Second
+
+// What processing a gateway callback did
+const (
+	CallbackOutcomeApplied        = "applied"         // Moved the deposit to a new status
+	CallbackOutcomePending        = "pending"         // Gateway still processing, nothing changed
+	CallbackOutcomeDuplicate      = "duplicate"       // Deposit already in the reported status
+	CallbackOutcomeConflict       = "conflict"        // Deposit already final in another status
+	CallbackOutcomeAmountMismatch = "amount_mismatch" // Paid amount differs from the deposit
+	CallbackOutcomeRejected       = "rejected"        // Failed verification or matched no deposit
+)
+
+var (
+	ErrDepositNotRefundable  = errors.New("deposit cannot be refunded")
+	ErrPaymentAmountMismatch = errors.New("paid amount does not match the deposit")
+)
+
+// depositTransitions lists the statuses a deposit may move to. Completed and
+// failed are final for gateway updates; only an admin refund leaves completed.
+var depositTransitions = map[string][]string{
+	DepositStatusPending:   {DepositStatusCompleted, DepositStatusFailed},
+	DepositStatusCompleted: {DepositStatusRefunded},
+}
+
+func canTransitionDeposit(from, to string) bool {
+	for _, allowed := range depositTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// DepositListener hears about deposits that complete, once the wallet credit
+// is committed
+type DepositListener interface {
+	DepositCompleted(deposit *models.Transaction)
+}
+
+// DepositService owns the lifecycle of wallet deposits: it records the
+// transaction, hands collection to a PaymentGateway and credits the wallet
+// exactly once when the gateway reports the payment complete.
+type DepositService interface {
+	InitiateDeposit(userID uuid.UUID, req *models.InitiateDepositRequest) (*PaymentSession, error)
+	HandleCallback(gateway string, callback *PaymentCallback) (*models.Transaction, error)
+	CheckStatus(transactionID uuid.UUID) (*models.Transaction, error)
+	ExpireDeposit(transactionID uuid.UUID) (*models.Transaction, error)
+	RefundDeposit(transactionID uuid.UUID, reason string) (*models.Transaction, error)
+	AddListener(listener DepositListener)
+}
+
+type depositService struct {
+	db            *gorm.DB
+	userRepo      repository.UserRepository
+	ledgerService LedgerService
+	gateways      map[string]PaymentGateway
+	listeners     []DepositListener
+	config        *config.Config
+}
+
+func NewDepositService(db *gorm.DB, userRepo repository.UserRepository, ledgerService LedgerService, gateways map[string]PaymentGateway, config *config.Config) DepositService {
+	return &depositService{
+		db:            db,
+		userRepo:      userRepo,
+		ledgerService: ledgerService,
+		gateways:      gateways,
+		config:        config,
+	}
+}
+
+// depositLedgerKey makes callbacks and status checks credit a deposit once
+func depositLedgerKey(transactionID uuid.UUID) string {
+	return "deposit:" + transactionID.String()
+}
+
+// AddListener registers a listener for completed deposits. Listeners are
+// added at startup, before any deposit is processed.
+func (s *depositService) AddListener(listener DepositListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+func (s *depositService) gateway(name string) (PaymentGateway, error) {
+	if name == "" {
+		name = GatewayPhonePe
+	}
+	gateway, ok := s.gateways[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownGateway, name)
+	}
+	return gateway, nil
+}
+
+func (s *depositService) InitiateDeposit(userID uuid.UUID, req *models.InitiateDepositRequest) (*PaymentSession, error) {
+	gateway, err := s.gateway(req.Gateway)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	amount := roundCurrency(req.Amount)
+	transaction := &models.Transaction{
+		ID:              uuid.New(),
+		UserID:          userID,
+		Amount:          amount,
+		Type:            TransactionTypeDeposit,
+		Status:          DepositStatusPending,
+		Gateway:         gateway.Name(),
+		RelatedEntityID: req.ContestID,
+		Description:     req.Purpose,
+	}
+	SingleBucket(WalletBucketDeposit, amount).ApplyTo(transaction)
+	if err := s.db.Create(transaction).Error; err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gatewayTimeout)
+	defer cancel()
+
+	session, err := gateway.Initiate(ctx, &PaymentInitiation{
+		TransactionID: transaction.ID,
+		UserID:        userID,
+		Amount:        amount,
+		MobileNumber:  user.PhoneNumber,
+		Purpose:       req.Purpose,
+	})
+	if err != nil {
+		s.db.Model(transaction).Update("status", DepositStatusFailed)
+		return nil, fmt.Errorf("failed to initiate payment: %w", err)
+	}
+
+	if session.OrderID != "" {
+		if err := s.db.Model(transaction).Update("order_id", session.OrderID).Error; err != nil {
+			return nil, fmt.Errorf("failed to save gateway order: %w", err)
+		}
+	}
+
+	log.Printf("💳 Deposit %s initiated via %s for user %s (₹%.2f)", transaction.ID, gateway.Name(), userID, amount)
+
+	return session, nil
+}
+
+// HandleCallback verifies a gateway notification and applies the payment
+// state it reports. Every callback is stored as received, with what it did,
+// so retries and disputes can be traced.
+func (s *depositService) HandleCallback(gatewayName string, callback *PaymentCallback) (*models.Transaction, error) {
+	gateway, err := s.gateway(gatewayName)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.GatewayCallback{
+		Gateway:   gateway.Name(),
+		Payload:   callback.Payload,
+		Signature: callback.Signature,
+		OrderID:   callback.OrderID,
+		PaymentID: callback.PaymentID,
+		Outcome:   CallbackOutcomeRejected,
+	}
+	defer func() {
+		if err := s.db.Create(record).Error; err != nil {
+			log.Printf("Error saving %s callback: %v", record.Gateway, err)
+		}
+	}()
+
+	status, err := gateway.VerifyCallback(callback)
+	if err != nil {
+		record.Error = err.Error()
+		return nil, err
+	}
+	record.OrderID = status.OrderID
+	record.PaymentID = status.PaymentID
+	record.GatewayState = status.GatewayState
+	record.Amount = status.Amount
+
+	transaction, outcome, err := s.applyStatus(gateway.Name(), status)
+	record.Outcome = outcome
+	if transaction != nil {
+		record.TransactionID = &transaction.ID
+	}
+	if err != nil {
+		record.Error = err.Error()
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// CheckStatus asks the gateway about a deposit that is still pending
+func (s *depositService) CheckStatus(transactionID uuid.UUID) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := s.db.First(&transaction, "id = ? AND type = ?", transactionID, TransactionTypeDeposit).Error
+	if err != nil {
+		return nil, fmt.Errorf("transaction not found")
+	}
+	if transaction.Status != DepositStatusPending {
+		return &transaction, nil
+	}
+
+	gateway, err := s.gateway(transaction.Gateway)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gatewayTimeout)
+	defer cancel()
+
+	status, err := gateway.Status(ctx, &transaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check payment status: %w", err)
+	}
+	status.TransactionID = transaction.ID
+
+	updated, _, err := s.applyStatus(gateway.Name(), status)
+	return updated, err
+}
+
+// ExpireDeposit fails a deposit the gateway never completed. A payment that
+// completes afterwards is not credited and shows up in reconciliation.
+func (s *depositService) ExpireDeposit(transactionID uuid.UUID) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := s.db.First(&transaction, "id = ? AND type = ?", transactionID, TransactionTypeDeposit).Error
+	if err != nil {
+		return nil, fmt.Errorf("transaction not found")
+	}
+
+	updated, _, err := s.applyStatus(transaction.Gateway, &PaymentStatus{
+		TransactionID: transaction.ID,
+		Status:        DepositStatusFailed,
+		GatewayState:  "EXPIRED",
+	})
+	return updated, err
+}
+
+// applyStatus moves a pending deposit to the status the gateway reported,
+// crediting the wallet on completion. The deposit row is locked, so a
+// callback racing a status check or its own retry is applied once; deposits
+// already in a final status are returned unchanged.
+func (s *depositService) applyStatus(gatewayName string, status *PaymentStatus) (*models.Transaction, string, error) {
+	var transaction models.Transaction
+	outcome := CallbackOutcomeRejected
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("type = ? AND gateway = ?", TransactionTypeDeposit, gatewayName)
+		if status.TransactionID != uuid.Nil {
+			query = query.Where("id = ?", status.TransactionID)
+		} else {
+			query = query.Where("order_id = ?", status.OrderID)
+		}
+		if err := query.First(&transaction).Error; err != nil {
+			return fmt.Errorf("transaction not found")
+		}
+
+		switch {
+		case status.Status == DepositStatusPending:
+			outcome = CallbackOutcomePending
+			return nil
+		case status.Status == transaction.Status:
+			outcome = CallbackOutcomeDuplicate
+			return nil
+		case !canTransitionDeposit(transaction.Status, status.Status):
+			outcome = CallbackOutcomeConflict
+			log.Printf("⚠️ Ignoring %s for deposit %s, already %s", status.GatewayState, transaction.ID, transaction.Status)
+			return nil
+		}
+
+		if status.Amount > 0 && roundCurrency(status.Amount) != roundCurrency(transaction.Amount) {
+			outcome = CallbackOutcomeAmountMismatch
+			return fmt.Errorf("%w: paid ₹%.2f, deposit is ₹%.2f", ErrPaymentAmountMismatch, status.Amount, transaction.Amount)
+		}
+
+		updates := map[string]interface{}{"status": status.Status}
+		if status.PaymentID != "" {
+			updates["payment_id"] = status.PaymentID
+		}
+
+		switch status.Status {
+		case DepositStatusCompleted:
+			err := s.ledgerService.RecordDeposit(tx, transaction.UserID, transaction.Amount, &transaction.ID, depositLedgerKey(transaction.ID))
+			if err != nil {
+				return fmt.Errorf("failed to credit wallet: %w", err)
+			}
+			log.Printf("✅ Deposit %s completed via %s, amount: ₹%.2f", transaction.ID, gatewayName, transaction.Amount)
+		case DepositStatusFailed:
+			log.Printf("❌ Deposit %s failed via %s (%s)", transaction.ID, gatewayName, status.GatewayState)
+		}
+
+		if err := tx.Model(&transaction).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update transaction: %w", err)
+		}
+		transaction.Status = status.Status
+		outcome = CallbackOutcomeApplied
+		return nil
+	})
+	if err != nil {
+		if transaction.ID == uuid.Nil {
+			return nil, outcome, err
+		}
+		return &transaction, outcome, err
+	}
+
+	if outcome == CallbackOutcomeApplied && transaction.Status == DepositStatusCompleted {
+		for _, listener := range s.listeners {
+			listener.DepositCompleted(&transaction)
+		}
+	}
+
+	return &transaction, outcome, nil
+}
+
+// RefundDeposit returns a completed deposit to its payment method. The money
+// must still be in the user's deposit bucket.
+func (s *depositService) RefundDeposit(transactionID uuid.UUID, reason string) (*models.Transaction, error) {
+	var refund *models.Transaction
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var deposit models.Transaction
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&deposit, "id = ? AND type = ?", transactionID, TransactionTypeDeposit).Error
+		if err != nil {
+			return fmt.Errorf("transaction not found")
+		}
+		if !canTransitionDeposit(deposit.Status, DepositStatusRefunded) {
+			return fmt.Errorf("%w: status is %s", ErrDepositNotRefundable, deposit.Status)
+		}
+
+		gateway, err := s.gateway(deposit.Gateway)
+		if err != nil {
+			return err
+		}
+
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", deposit.UserID).Error; err != nil {
+			return fmt.Errorf("user not found")
+		}
+		if user.DepositBalance < deposit.Amount {
+			return fmt.Errorf("%w: only ₹%.2f of deposits left in the wallet", ErrInsufficientFunds, user.DepositBalance)
+		}
+
+		refund = &models.Transaction{
+			ID:              uuid.New(),
+			UserID:          deposit.UserID,
+			Amount:          deposit.Amount,
+			Type:            TransactionTypeDepositRefund,
+			Status:          "completed",
+			Gateway:         deposit.Gateway,
+			RelatedEntityID: &deposit.ID,
+			Description:     reason,
+		}
+		SingleBucket(WalletBucketDeposit, deposit.Amount).ApplyTo(refund)
+
+		_, err = s.ledgerService.Post(tx, &JournalEntryRequest{
+			Kind:           JournalKindDepositRefund,
+			ReferenceID:    &deposit.ID,
+			TransactionID:  &refund.ID,
+			IdempotencyKey: fmt.Sprintf("deposit_refund:%s", deposit.ID),
+			Description:    "Deposit refund",
+			Postings: []LedgerPosting{
+				Debit(UserDepositAccount(deposit.UserID), deposit.Amount),
+				Credit(GatewayClearingAccount(), deposit.Amount),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to debit wallet: %w", err)
+		}
+
+		// The gateway is called last so a failure rolls the debit back
+		ctx, cancel := context.WithTimeout(context.Background(), gatewayTimeout)
+		defer cancel()
+
+		result, err := gateway.Refund(ctx, &RefundRequest{
+			RefundID:      refund.ID,
+			TransactionID: deposit.ID,
+			UserID:        deposit.UserID,
+			OrderID:       deposit.OrderID,
+			PaymentID:     deposit.PaymentID,
+			Amount:        deposit.Amount,
+		})
+		if err != nil {
+			return fmt.Errorf("gateway refund failed: %w", err)
+		}
+		refund.PaymentID = result.Reference
+
+		if err := tx.Create(refund).Error; err != nil {
+			return fmt.Errorf("failed to create refund transaction: %w", err)
+		}
+		return tx.Model(&deposit).Update("status", DepositStatusRefunded).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("↩️ Deposit %s refunded (₹%.2f): %s", transactionID, refund.Amount, reason)
+
+	return refund, nil
+}
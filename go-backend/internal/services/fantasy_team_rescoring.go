@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// teamPointsDelta is the change in one fantasy team's total caused by a
+// change in one of its players' points, with the total it led to
+type teamPointsDelta struct {
+	TeamID      uuid.UUID
+	ContestID   uuid.UUID
+	Delta       float64
+	TotalPoints float64
+}
+
+// applyPlayerPointsDelta adds the change in a player's points to every
+// fantasy team holding the player in a contest for the match, instead of
+// recomputing each team from scratch. Contests are grouped by the ruleset
+// they score with and each group is updated with a single statement.
+func (s *scoringService) applyPlayerPointsDelta(tx *gorm.DB, previous, current *models.PlayerMatchStats) ([]teamPointsDelta, error) {
+	teamsWithPlayer := tx.Model(&models.FantasyTeam{}).
+		Select("fantasy_teams.contest_id").
+		Joins("JOIN fantasy_team_players ON fantasy_team_players.fantasy_team_id = fantasy_teams.id").
+		Where("fantasy_team_players.player_id = ?", current.PlayerID)
+
+	var contests []models.Contest
+	if err := tx.Where("match_id = ? AND id IN (?)", current.MatchID, teamsWithPlayer).Find(&contests).Error; err != nil {
+		return nil, fmt.Errorf("failed to get contests holding player: %w", err)
+	}
+
+	// Unpinned contests all score with the match's current rules
+	type contestGroup struct {
+		engine     *ScoringRuleEngine
+		contestIDs []uuid.UUID
+	}
+	groups := make(map[string]*contestGroup)
+	for i := range contests {
+		key := "match"
+		if contests[i].ScoringRulesetID != nil {
+			key = contests[i].ScoringRulesetID.String()
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			engine, err := s.getRuleEngineForContest(&contests[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to load scoring rules for contest %s: %w", contests[i].ID, err)
+			}
+			group = &contestGroup{engine: engine}
+			groups[key] = group
+		}
+		group.contestIDs = append(group.contestIDs, contests[i].ID)
+	}
+
+	var deltas []teamPointsDelta
+	for _, group := range groups {
+		delta := group.engine.Evaluate(current) - group.engine.Evaluate(previous)
+		if delta == 0 {
+			continue
+		}
+
+		var updated []teamPointsDelta
+		err := tx.Raw(`
+			UPDATE fantasy_teams
+			SET total_points = fantasy_teams.total_points + ftp.delta, updated_at = NOW()
+			FROM (
+				SELECT fantasy_team_id,
+					? * CASE WHEN is_captain THEN ? WHEN is_vice_captain THEN ? ELSE 1 END AS delta
+				FROM fantasy_team_players
+				WHERE player_id = ?
+			) AS ftp
+			WHERE ftp.fantasy_team_id = fantasy_teams.id AND fantasy_teams.contest_id IN ?
+			RETURNING fantasy_teams.id AS team_id, fantasy_teams.contest_id, ftp.delta, fantasy_teams.total_points`,
+			delta, CaptainMultiplier, ViceCaptainMultiplier, current.PlayerID, group.contestIDs,
+		).Scan(&updated).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply points delta to fantasy teams: %w", err)
+		}
+
+		deltas = append(deltas, updated...)
+	}
+
+	return deltas, nil
+}
+
+// publishTeamPointsDeltas mirrors committed fantasy team totals into the
+// Redis leaderboards and notifies leaderboard subscribers. Totals are set
+// rather than incremented, so an update racing a leaderboard refresh is at
+// worst stale until the next one instead of counted twice or lost.
+func (s *scoringService) publishTeamPointsDeltas(matchID uuid.UUID, deltas []teamPointsDelta) {
+	if len(deltas) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	pipe := s.rdb.Pipeline()
+	for _, d := range deltas {
+		leaderboardKey := fmt.Sprintf("leaderboard:%s", d.ContestID)
+		pipe.ZAdd(ctx, leaderboardKey, &redis.Z{Score: d.TotalPoints, Member: d.TeamID.String()})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Error updating Redis leaderboard: %v", err)
+	}
+
+	log.Printf("🏆 Applied %d fantasy team score deltas for match %s", len(deltas), matchID)
+
+	s.publishLeaderboardUpdate(matchID)
+}
+
+func (s *scoringService) publishLeaderboardUpdate(matchID uuid.UUID) {
+	updateEvent := map[string]interface{}{
+		"type":     "leaderboard_update",
+		"match_id": matchID.String(),
+	}
+	eventData, _ := json.Marshal(updateEvent)
+
+	ctx := context.Background()
+	if err := s.rdb.Publish(ctx, "leaderboard_updates", eventData).Err(); err != nil {
+		log.Printf("Error publishing leaderboard update: %v", err)
+	}
+}
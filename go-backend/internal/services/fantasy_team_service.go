@@ -1,74 +1,288 @@
 package services
 
 import (
+	"encoding/json"
+	"errors"
 	"esports-fantasy-backend/internal/models"
 	"esports-fantasy-backend/internal/repository"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTeamNotFound = errors.New("fantasy team not found")
+	ErrTeamLocked   = errors.New("contest has locked, the team can no longer be edited")
+	// ErrDuplicateLineup is returned when a user's entries in a contest would
+	// have the same players, captain and vice-captain
+	ErrDuplicateLineup = errors.New("you already have a team with this lineup in the contest")
 )
 
 type FantasyTeamService interface {
-	CreateFantasyTeam(userID uuid.UUID, req *models.CreateTeamRequest) (*models.FantasyTeam, error)
+	CreateFantasyTeam(tx *gorm.DB, userID uuid.UUID, req *models.CreateTeamRequest) (*models.FantasyTeam, error)
+	UpdateFantasyTeam(userID, teamID uuid.UUID, req *models.UpdateTeamRequest) (*models.FantasyTeam, error)
+	ApplySavedLineup(userID, teamID, lineupID uuid.UUID, req *models.UpdateTeamRequest) (*models.FantasyTeam, error)
+	GetTeamHistory(userID, teamID uuid.UUID) ([]models.FantasyTeamEdit, error)
 	GetUserTeams(userID uuid.UUID) ([]models.FantasyTeam, error)
 	GetTeamByID(id uuid.UUID) (*models.FantasyTeam, error)
 	ValidateTeamCreation(req *models.CreateTeamRequest) error
+	ValidateLineup(matchID uuid.UUID, playerIDs []uuid.UUID, captainID, viceCaptainID uuid.UUID) error
 }
 
 type fantasyTeamService struct {
+	db              *gorm.DB
 	fantasyTeamRepo repository.FantasyTeamRepository
 	playerRepo      repository.PlayerRepository
 }
 
-func NewFantasyTeamService(fantasyTeamRepo repository.FantasyTeamRepository, playerRepo repository.PlayerRepository) FantasyTeamService {
+func NewFantasyTeamService(db *gorm.DB, fantasyTeamRepo repository.FantasyTeamRepository, playerRepo repository.PlayerRepository) FantasyTeamService {
 	return &fantasyTeamService{
+		db:              db,
 		fantasyTeamRepo: fantasyTeamRepo,
 		playerRepo:      playerRepo,
 	}
 }
 
-func (s *fantasyTeamService) CreateFantasyTeam(userID uuid.UUID, req *models.CreateTeamRequest) (*models.FantasyTeam, error) {
+// CreateFantasyTeam saves a validated team and its players in tx, or in a
+// transaction of its own when tx is nil. Paying for the contest is up to the
+// caller; see ContestJoinService.
+func (s *fantasyTeamService) CreateFantasyTeam(tx *gorm.DB, userID uuid.UUID, req *models.CreateTeamRequest) (*models.FantasyTeam, error) {
+	if tx == nil {
+		var team *models.FantasyTeam
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			team, err = s.CreateFantasyTeam(tx, userID, req)
+			return err
+		})
+		return team, err
+	}
+
 	// Validate team creation
 	if err := s.ValidateTeamCreation(req); err != nil {
 		return nil, err
 	}
 
-	// Check if user already has a team in this contest
-	exists, err := s.fantasyTeamRepo.CheckUserAlreadyInContest(userID, req.ContestID)
+	var contest models.Contest
+	if err := tx.First(&contest, "id = ?", req.ContestID).Error; err != nil {
+		return nil, fmt.Errorf("contest not found: %w", err)
+	}
+
+	// Each entry is a team of its own, up to the contest's limit per user
+	existing, err := s.userEntries(tx, userID, req.ContestID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check existing team: %w", err)
+		return nil, err
+	}
+	limit := contest.MaxEntriesPerUser
+	if limit < 1 {
+		limit = 1
 	}
-	if exists {
-		return nil, fmt.Errorf("user already has a team in this contest")
+	if len(existing) >= limit {
+		return nil, ErrEntryLimitReached
+	}
+	if hasLineup(existing, uuid.Nil, req.PlayerIDs, req.CaptainID, req.ViceCaptainID) {
+		return nil, ErrDuplicateLineup
 	}
 
 	// Create fantasy team
+	entryNumber := len(existing) + 1
 	fantasyTeam := &models.FantasyTeam{
-		ID:        uuid.New(),
-		UserID:    userID,
-		ContestID: req.ContestID,
-		TeamName:  req.TeamName,
+		ID:            uuid.New(),
+		UserID:        userID,
+		ContestID:     req.ContestID,
+		TeamName:      req.TeamName,
+		EntryNumber:   entryNumber,
+		EntryLabel:    models.EntryLabel(entryNumber),
+		SavedLineupID: req.SavedLineupID,
 	}
 
-	if err := s.fantasyTeamRepo.CreateFantasyTeam(fantasyTeam); err != nil {
+	// Add players to the team
+	fantasyTeam.Players = teamPlayers(fantasyTeam.ID, req.PlayerIDs, req.CaptainID, req.ViceCaptainID)
+
+	// Creating the team also creates its player rows
+	if err := tx.Create(fantasyTeam).Error; err != nil {
 		return nil, fmt.Errorf("failed to create fantasy team: %w", err)
 	}
 
-	// Add players to the team
-	for _, playerID := range req.PlayerIDs {
-		fantasyPlayer := models.FantasyTeamPlayer{
-			FantasyTeamID: fantasyTeam.ID,
+	return fantasyTeam, nil
+}
+
+func teamPlayers(teamID uuid.UUID, playerIDs []uuid.UUID, captainID, viceCaptainID uuid.UUID) []models.FantasyTeamPlayer {
+	players := make([]models.FantasyTeamPlayer, 0, len(playerIDs))
+	for _, playerID := range playerIDs {
+		players = append(players, models.FantasyTeamPlayer{
+			FantasyTeamID: teamID,
 			PlayerID:      playerID,
-			IsCaptain:     playerID == req.CaptainID,
-			IsViceCaptain: playerID == req.ViceCaptainID,
+			IsCaptain:     playerID == captainID,
+			IsViceCaptain: playerID == viceCaptainID,
+		})
+	}
+	return players
+}
+
+// userEntries loads a user's teams in a contest, with their players
+func (s *fantasyTeamService) userEntries(tx *gorm.DB, userID, contestID uuid.UUID) ([]models.FantasyTeam, error) {
+	var teams []models.FantasyTeam
+	err := tx.Preload("Players").
+		Where("user_id = ? AND contest_id = ?", userID, contestID).
+		Order("entry_number ASC").
+		Find(&teams).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing entries: %w", err)
+	}
+	return teams, nil
+}
+
+// lineupKey identifies a lineup by its players, captain and vice-captain,
+// whatever order the players were picked in
+func lineupKey(playerIDs []uuid.UUID, captainID, viceCaptainID uuid.UUID) string {
+	ids := make([]string, 0, len(playerIDs))
+	for _, playerID := range playerIDs {
+		ids = append(ids, playerID.String())
+	}
+	sort.Strings(ids)
+	return fmt.Sprintf("%s|c:%s|vc:%s", strings.Join(ids, ","), captainID, viceCaptainID)
+}
+
+// hasLineup reports whether any team other than exceptID already has the lineup
+func hasLineup(teams []models.FantasyTeam, exceptID uuid.UUID, playerIDs []uuid.UUID, captainID, viceCaptainID uuid.UUID) bool {
+	key := lineupKey(playerIDs, captainID, viceCaptainID)
+	for i := range teams {
+		if teams[i].ID == exceptID {
+			continue
+		}
+		lineup := teamLineup(&teams[i])
+		if lineupKey(lineup.PlayerIDs, lineup.CaptainID, lineup.ViceCaptainID) == key {
+			return true
 		}
+	}
+	return false
+}
 
-		// Note: This should be handled in a transaction in production
-		// For now, we'll assume a method exists to create fantasy team players
-		_ = fantasyPlayer // Placeholder for now
+// teamLineup snapshots a team's current players for the edit history
+func teamLineup(team *models.FantasyTeam) models.FantasyTeamLineup {
+	lineup := models.FantasyTeamLineup{TeamName: team.TeamName, PlayerIDs: []uuid.UUID{}}
+	for _, player := range team.Players {
+		lineup.PlayerIDs = append(lineup.PlayerIDs, player.PlayerID)
+		if player.IsCaptain {
+			lineup.CaptainID = player.PlayerID
+		}
+		if player.IsViceCaptain {
+			lineup.ViceCaptainID = player.PlayerID
+		}
 	}
+	return lineup
+}
 
-	return fantasyTeam, nil
+// UpdateFantasyTeam replaces a team's players, captain and vice-captain and
+// records the change. The contest row is locked so an edit cannot land after
+// the contest locks. A team edited on its own stops following its saved lineup.
+func (s *fantasyTeamService) UpdateFantasyTeam(userID, teamID uuid.UUID, req *models.UpdateTeamRequest) (*models.FantasyTeam, error) {
+	return s.updateTeam(userID, teamID, req, nil)
+}
+
+// ApplySavedLineup brings a team that follows a saved lineup up to date with it
+func (s *fantasyTeamService) ApplySavedLineup(userID, teamID, lineupID uuid.UUID, req *models.UpdateTeamRequest) (*models.FantasyTeam, error) {
+	return s.updateTeam(userID, teamID, req, &lineupID)
+}
+
+func (s *fantasyTeamService) updateTeam(userID, teamID uuid.UUID, req *models.UpdateTeamRequest, lineupID *uuid.UUID) (*models.FantasyTeam, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var team models.FantasyTeam
+		err := tx.Preload("Players").First(&team, "id = ? AND user_id = ?", teamID, userID).Error
+		if err != nil {
+			return ErrTeamNotFound
+		}
+
+		var contest models.Contest
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&contest, "id = ?", team.ContestID).Error
+		if err != nil {
+			return fmt.Errorf("contest not found: %w", err)
+		}
+		if contest.LockedAt != nil || !strings.EqualFold(contest.Status, ContestStatusOpen) {
+			return ErrTeamLocked
+		}
+
+		teamName := req.TeamName
+		if teamName == "" {
+			teamName = team.TeamName
+		}
+		if err := s.ValidateTeamCreation(&models.CreateTeamRequest{
+			ContestID:     team.ContestID,
+			TeamName:      teamName,
+			PlayerIDs:     req.PlayerIDs,
+			CaptainID:     req.CaptainID,
+			ViceCaptainID: req.ViceCaptainID,
+		}); err != nil {
+			return err
+		}
+
+		entries, err := s.userEntries(tx, userID, team.ContestID)
+		if err != nil {
+			return err
+		}
+		if hasLineup(entries, team.ID, req.PlayerIDs, req.CaptainID, req.ViceCaptainID) {
+			return ErrDuplicateLineup
+		}
+
+		previous, err := json.Marshal(teamLineup(&team))
+		if err != nil {
+			return fmt.Errorf("failed to encode lineup: %w", err)
+		}
+		next, err := json.Marshal(models.FantasyTeamLineup{
+			TeamName:      teamName,
+			PlayerIDs:     req.PlayerIDs,
+			CaptainID:     req.CaptainID,
+			ViceCaptainID: req.ViceCaptainID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encode lineup: %w", err)
+		}
+
+		if err := tx.Where("fantasy_team_id = ?", team.ID).Delete(&models.FantasyTeamPlayer{}).Error; err != nil {
+			return fmt.Errorf("failed to remove old players: %w", err)
+		}
+		if err := tx.Create(teamPlayers(team.ID, req.PlayerIDs, req.CaptainID, req.ViceCaptainID)).Error; err != nil {
+			return fmt.Errorf("failed to save players: %w", err)
+		}
+		err = tx.Model(&team).Updates(map[string]interface{}{
+			"team_name":       teamName,
+			"saved_lineup_id": lineupID,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update team: %w", err)
+		}
+
+		return tx.Create(&models.FantasyTeamEdit{
+			FantasyTeamID:  team.ID,
+			UserID:         userID,
+			PreviousLineup: string(previous),
+			NewLineup:      string(next),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.fantasyTeamRepo.GetTeamByID(teamID)
+}
+
+// GetTeamHistory lists the edits made to a user's team, oldest first
+func (s *fantasyTeamService) GetTeamHistory(userID, teamID uuid.UUID) ([]models.FantasyTeamEdit, error) {
+	var team models.FantasyTeam
+	if err := s.db.First(&team, "id = ? AND user_id = ?", teamID, userID).Error; err != nil {
+		return nil, ErrTeamNotFound
+	}
+
+	var edits []models.FantasyTeamEdit
+	err := s.db.Where("fantasy_team_id = ?", teamID).Order("created_at ASC").Find(&edits).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team history: %w", err)
+	}
+	return edits, nil
 }
 
 func (s *fantasyTeamService) GetUserTeams(userID uuid.UUID) ([]models.FantasyTeam, error) {
@@ -87,51 +301,53 @@ func (s *fantasyTeamService) GetTeamByID(id uuid.UUID) (*models.FantasyTeam, err
 	return team, nil
 }
 
+// ValidateTeamCreation checks a team against the composition rules of its
+// contest's game. Broken rules come back together as a *TeamValidationError.
 func (s *fantasyTeamService) ValidateTeamCreation(req *models.CreateTeamRequest) error {
-	// Check if we have exactly 5 players (BGMI/PUBG standard)
-	if len(req.PlayerIDs) != 5 {
-		return fmt.Errorf("team must have exactly 5 players")
+	var contest models.Contest
+	if err := s.db.First(&contest, "id = ?", req.ContestID).Error; err != nil {
+		return fmt.Errorf("contest not found: %w", err)
 	}
+	return s.ValidateLineup(contest.MatchID, req.PlayerIDs, req.CaptainID, req.ViceCaptainID)
+}
 
-	// Check if captain and vice-captain are in the player list
-	captainFound := false
-	viceCaptainFound := false
-	for _, playerID := range req.PlayerIDs {
-		if playerID == req.CaptainID {
-			captainFound = true
-		}
-		if playerID == req.ViceCaptainID {
-			viceCaptainFound = true
-		}
+// ValidateLineup checks a lineup against the rules of the match's game and
+// the squads playing in the match
+func (s *fantasyTeamService) ValidateLineup(matchID uuid.UUID, playerIDs []uuid.UUID, captainID, viceCaptainID uuid.UUID) error {
+	var match models.Match
+	if err := s.db.Preload("Tournament").First(&match, "id = ?", matchID).Error; err != nil {
+		return fmt.Errorf("match not found: %w", err)
 	}
 
-	if !captainFound {
-		return fmt.Errorf("captain must be one of the selected players")
+	// Tournaments from before games were added have no game to check against
+	var game *models.Game
+	if match.Tournament.GameID != uuid.Nil {
+		game = &models.Game{}
+		if err := s.db.First(game, "id = ?", match.Tournament.GameID).Error; err != nil {
+			return fmt.Errorf("game not found: %w", err)
+		}
 	}
-	if !viceCaptainFound {
-		return fmt.Errorf("vice captain must be one of the selected players")
+	rules, err := gameTeamRules(game)
+	if err != nil {
+		return err
+	}
+
+	var teamIDs []uuid.UUID
+	if err := s.db.Model(&models.MatchTeam{}).Where("match_id = ?", matchID).Pluck("esports_team_id", &teamIDs).Error; err != nil {
+		return fmt.Errorf("failed to get match teams: %w", err)
 	}
-	if req.CaptainID == req.ViceCaptainID {
-		return fmt.Errorf("captain and vice captain must be different players")
+	matchTeams := make(map[uuid.UUID]bool, len(teamIDs))
+	for _, teamID := range teamIDs {
+		matchTeams[teamID] = true
 	}
 
-	// Check if all players exist
-	players, err := s.playerRepo.GetPlayersByIDs(req.PlayerIDs)
+	players, err := s.playerRepo.GetPlayersByIDs(playerIDs)
 	if err != nil {
 		return fmt.Errorf("failed to validate players: %w", err)
 	}
-	if len(players) != len(req.PlayerIDs) {
-		return fmt.Errorf("some players not found")
-	}
 
-	// Calculate total credit value (assuming 100 credits budget)
-	totalCredits := 0.0
-	for _, player := range players {
-		totalCredits += player.CreditValue
-	}
-	if totalCredits > 100.0 {
-		return fmt.Errorf("team exceeds credit limit (%.1f/100)", totalCredits)
+	if violations := checkLineup(rules, game, matchTeams, playerIDs, captainID, viceCaptainID, players); violations != nil {
+		return violations
 	}
-
 	return nil
-}
\ No newline at end of file
+}
@@ -6,8 +6,11 @@ import (
 	"esports-fantasy-backend/internal/models"
 	"esports-fantasy-backend/internal/repository"
 	"fmt"
+	"sort"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GameService interface {
@@ -24,19 +27,34 @@ type GameService interface {
 	GetScoringRulesByGame(gameID uuid.UUID) ([]models.GameScoringRule, error)
 	UpdateScoringRule(id uuid.UUID, req *models.CreateScoringRuleRequest) error
 	DeleteScoringRule(id uuid.UUID) error
+
+	// Battle-royale placement points
+	GetPlacementPoints(gameID uuid.UUID) ([]models.GamePlacementPoint, error)
+	SetPlacementPoints(gameID uuid.UUID, req *models.SetPlacementPointsRequest) ([]models.GamePlacementPoint, error)
+
+	// Versioned scoring rulesets
+	GetActiveScoringRuleset(gameID uuid.UUID) (*models.ScoringRuleset, error)
+	GetScoringRulesets(gameID uuid.UUID) ([]models.ScoringRuleset, error)
+	DiffScoringRulesets(gameID uuid.UUID, fromVersion, toVersion int) ([]models.ScoringRulesetDiffEntry, error)
 }
 
 type gameService struct {
+	db             *gorm.DB
 	gameRepo       repository.GameRepository
 	scoringRepo    repository.GameScoringRuleRepository
+	rulesetRepo    repository.ScoringRulesetRepository
+	placementRepo  repository.PlacementRepository
 	config         *config.Config
 }
 
-func NewGameService(gameRepo repository.GameRepository, scoringRepo repository.GameScoringRuleRepository, config *config.Config) GameService {
+func NewGameService(db *gorm.DB, gameRepo repository.GameRepository, scoringRepo repository.GameScoringRuleRepository, rulesetRepo repository.ScoringRulesetRepository, placementRepo repository.PlacementRepository, config *config.Config) GameService {
 	return &gameService{
-		gameRepo:    gameRepo,
-		scoringRepo: scoringRepo,
-		config:      config,
+		db:            db,
+		gameRepo:      gameRepo,
+		scoringRepo:   scoringRepo,
+		rulesetRepo:   rulesetRepo,
+		placementRepo: placementRepo,
+		config:        config,
 	}
 }
 
@@ -53,6 +71,14 @@ func (s *gameService) CreateGame(req *models.CreateGameRequest) (*models.Game, e
 		return nil, fmt.Errorf("failed to serialize player roles: %w", err)
 	}
 
+	compositionJSON := "{}"
+	if req.TeamComposition != nil {
+		compositionJSON, err = encodeTeamComposition(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	game := &models.Game{
 		Name:              req.Name,
 		DisplayName:       req.DisplayName,
@@ -61,6 +87,7 @@ func (s *gameService) CreateGame(req *models.CreateGameRequest) (*models.Game, e
 		MaxPlayersPerTeam: req.MaxPlayersPerTeam,
 		MinPlayersPerTeam: req.MinPlayersPerTeam,
 		PlayerRoles:       string(rolesJSON),
+		TeamComposition:   compositionJSON,
 		ScoringRules:      "{}",
 	}
 
@@ -71,6 +98,21 @@ func (s *gameService) CreateGame(req *models.CreateGameRequest) (*models.Game, e
 	return game, nil
 }
 
+// encodeTeamComposition checks the requested team rules fit the game's roles
+// and team size and encodes them for storage
+func encodeTeamComposition(req *models.CreateGameRequest) (string, error) {
+	err := validateTeamComposition(req.TeamComposition, req.PlayerRoles, req.MinPlayersPerTeam, req.MaxPlayersPerTeam)
+	if err != nil {
+		return "", err
+	}
+
+	compositionJSON, err := json.Marshal(req.TeamComposition)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize team composition: %w", err)
+	}
+	return string(compositionJSON), nil
+}
+
 func (s *gameService) GetAllGames() ([]models.Game, error) {
 	return s.gameRepo.GetAll()
 }
@@ -109,6 +151,12 @@ func (s *gameService) UpdateGame(id uuid.UUID, req *models.CreateGameRequest) er
 	game.MaxPlayersPerTeam = req.MaxPlayersPerTeam
 	game.MinPlayersPerTeam = req.MinPlayersPerTeam
 	game.PlayerRoles = string(rolesJSON)
+	if req.TeamComposition != nil {
+		game.TeamComposition, err = encodeTeamComposition(req)
+		if err != nil {
+			return err
+		}
+	}
 
 	if err := s.gameRepo.Update(game); err != nil {
 		return fmt.Errorf("failed to update game: %w", err)
@@ -140,12 +188,6 @@ func (s *gameService) DeleteGame(id uuid.UUID) error {
 
 // Scoring rules management
 func (s *gameService) CreateScoringRule(req *models.CreateScoringRuleRequest) (*models.GameScoringRule, error) {
-	// Verify game exists
-	_, err := s.gameRepo.GetByID(req.GameID)
-	if err != nil {
-		return nil, fmt.Errorf("game not found: %w", err)
-	}
-
 	rule := &models.GameScoringRule{
 		GameID:      req.GameID,
 		ActionType:  req.ActionType,
@@ -154,8 +196,15 @@ func (s *gameService) CreateScoringRule(req *models.CreateScoringRuleRequest) (*
 		IsActive:    true,
 	}
 
-	if err := s.scoringRepo.Create(rule); err != nil {
-		return nil, fmt.Errorf("failed to create scoring rule: %w", err)
+	err := s.inGamesTx(func(tx *gorm.DB) error {
+		if err := tx.Create(rule).Error; err != nil {
+			return fmt.Errorf("failed to create scoring rule: %w", err)
+		}
+		_, err := s.publishScoringRuleset(tx, rule.GameID)
+		return err
+	}, req.GameID)
+	if err != nil {
+		return nil, err
 	}
 
 	return rule, nil
@@ -171,26 +220,281 @@ func (s *gameService) UpdateScoringRule(id uuid.UUID, req *models.CreateScoringR
 		return fmt.Errorf("failed to get scoring rule: %w", err)
 	}
 
-	// Verify game exists if changed
-	if rule.GameID != req.GameID {
-		_, err := s.gameRepo.GetByID(req.GameID)
-		if err != nil {
-			return fmt.Errorf("game not found: %w", err)
+	previousGameID := rule.GameID
+	return s.inGamesTx(func(tx *gorm.DB) error {
+		// The rule may have moved game before the locks were taken
+		var current models.GameScoringRule
+		if err := tx.First(&current, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to get scoring rule: %w", err)
+		}
+		if current.GameID != previousGameID {
+			return fmt.Errorf("scoring rule %s changed game while being updated", id)
+		}
+
+		current.GameID = req.GameID
+		current.ActionType = req.ActionType
+		current.Points = req.Points
+		current.Description = req.Description
+		if err := tx.Save(&current).Error; err != nil {
+			return fmt.Errorf("failed to update scoring rule: %w", err)
 		}
+
+		// Rule edits never touch existing rulesets, they publish a new version
+		if previousGameID != current.GameID {
+			if _, err := s.publishScoringRuleset(tx, previousGameID); err != nil {
+				return err
+			}
+		}
+		_, err := s.publishScoringRuleset(tx, current.GameID)
+		return err
+	}, previousGameID, req.GameID)
+}
+
+func (s *gameService) DeleteScoringRule(id uuid.UUID) error {
+	rule, err := s.scoringRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get scoring rule: %w", err)
 	}
 
-	rule.GameID = req.GameID
-	rule.ActionType = req.ActionType
-	rule.Points = req.Points
-	rule.Description = req.Description
+	return s.inGamesTx(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.GameScoringRule{}, "id = ? AND game_id = ?", id, rule.GameID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete scoring rule: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("scoring rule %s changed while being deleted", id)
+		}
+
+		_, err := s.publishScoringRuleset(tx, rule.GameID)
+		return err
+	}, rule.GameID)
+}
+
+// inGamesTx runs fn in a transaction holding the games' rows FOR UPDATE, so
+// rule edits and ruleset version numbers are serialised per game. Games are
+// locked in ID order so two edits cannot deadlock.
+func (s *gameService) inGamesTx(fn func(tx *gorm.DB) error, gameIDs ...uuid.UUID) error {
+	sort.Slice(gameIDs, func(i, j int) bool { return gameIDs[i].String() < gameIDs[j].String() })
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		for i, gameID := range gameIDs {
+			if i > 0 && gameID == gameIDs[i-1] {
+				continue
+			}
+			var game models.Game
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&game, "id = ?", gameID).Error; err != nil {
+				return fmt.Errorf("game not found: %w", err)
+			}
+		}
+		return fn(tx)
+	})
+}
+
+// Versioned scoring rulesets
+
+func (s *gameService) GetPlacementPoints(gameID uuid.UUID) ([]models.GamePlacementPoint, error) {
+	return s.placementRepo.GetPointsByGameID(gameID)
+}
+
+// SetPlacementPoints replaces a game's placement points table and publishes
+// a new ruleset version containing it
+func (s *gameService) SetPlacementPoints(gameID uuid.UUID, req *models.SetPlacementPointsRequest) ([]models.GamePlacementPoint, error) {
+	points := make([]models.GamePlacementPoint, 0, len(req.Placements))
+	seen := make(map[int]bool)
+	for _, entry := range req.Placements {
+		if entry.Placement < 1 {
+			return nil, fmt.Errorf("placement must be at least 1")
+		}
+		if seen[entry.Placement] {
+			return nil, fmt.Errorf("placement %d is listed more than once", entry.Placement)
+		}
+		seen[entry.Placement] = true
 
-	if err := s.scoringRepo.Update(rule); err != nil {
-		return fmt.Errorf("failed to update scoring rule: %w", err)
+		points = append(points, models.GamePlacementPoint{
+			GameID:    gameID,
+			Placement: entry.Placement,
+			Points:    entry.Points,
+		})
 	}
 
-	return nil
+	err := s.inGamesTx(func(tx *gorm.DB) error {
+		if err := tx.Where("game_id = ?", gameID).Delete(&models.GamePlacementPoint{}).Error; err != nil {
+			return fmt.Errorf("failed to save placement points: %w", err)
+		}
+		if len(points) > 0 {
+			if err := tx.Create(&points).Error; err != nil {
+				return fmt.Errorf("failed to save placement points: %w", err)
+			}
+		}
+		_, err := s.publishScoringRuleset(tx, gameID)
+		return err
+	}, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.placementRepo.GetPointsByGameID(gameID)
 }
 
-func (s *gameService) DeleteScoringRule(id uuid.UUID) error {
-	return s.scoringRepo.Delete(id)
+// gameScoringRules returns the game's active scoring rules together with its
+// placement table expressed as placement rules. A game with only placement
+// points gets the default action rules with them.
+func (s *gameService) gameScoringRules(tx *gorm.DB, gameID uuid.UUID) ([]models.GameScoringRule, error) {
+	var rules []models.GameScoringRule
+	err := tx.Where("game_id = ? AND is_active = ?", gameID, true).Order("action_type ASC").Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scoring rules: %w", err)
+	}
+
+	var placements []models.GamePlacementPoint
+	if err := tx.Where("game_id = ?", gameID).Order("placement ASC").Find(&placements).Error; err != nil {
+		return nil, fmt.Errorf("failed to get placement points: %w", err)
+	}
+
+	return withPlacementRules(rules, placements), nil
+}
+
+// publishScoringRuleset snapshots the game's active scoring rules and
+// placement table into a new immutable ruleset version. tx must hold the
+// game's row lock, which keeps version numbers from clashing.
+func (s *gameService) publishScoringRuleset(tx *gorm.DB, gameID uuid.UUID) (*models.ScoringRuleset, error) {
+	rules, err := s.gameScoringRules(tx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make([]models.ScoringRulesetRule, 0, len(rules))
+	for _, rule := range rules {
+		snapshot = append(snapshot, models.ScoringRulesetRule{
+			ActionType:  rule.ActionType,
+			Points:      rule.Points,
+			Description: rule.Description,
+		})
+	}
+
+	rulesJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize scoring ruleset: %w", err)
+	}
+
+	var latest int
+	err = tx.Model(&models.ScoringRuleset{}).
+		Where("game_id = ?", gameID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest ruleset version: %w", err)
+	}
+
+	ruleset := &models.ScoringRuleset{
+		GameID:  gameID,
+		Version: latest + 1,
+		Rules:   string(rulesJSON),
+	}
+
+	if err := tx.Create(ruleset).Error; err != nil {
+		return nil, fmt.Errorf("failed to publish scoring ruleset: %w", err)
+	}
+
+	return ruleset, nil
+}
+
+// GetActiveScoringRuleset returns the latest ruleset version for a game,
+// publishing the first version from the current rules if none exists yet.
+// Returns nil when the game has no scoring rules or placement points at all.
+func (s *gameService) GetActiveScoringRuleset(gameID uuid.UUID) (*models.ScoringRuleset, error) {
+	ruleset, err := s.rulesetRepo.GetLatestByGameID(gameID)
+	if err == nil {
+		return ruleset, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get scoring ruleset: %w", err)
+	}
+
+	err = s.inGamesTx(func(tx *gorm.DB) error {
+		// Another caller may have published while this one waited for the lock
+		var latest models.ScoringRuleset
+		err := tx.Where("game_id = ?", gameID).Order("version DESC").First(&latest).Error
+		if err == nil {
+			ruleset = &latest
+			return nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to get scoring ruleset: %w", err)
+		}
+
+		rules, err := s.gameScoringRules(tx, gameID)
+		if err != nil || len(rules) == 0 {
+			return err
+		}
+
+		ruleset, err = s.publishScoringRuleset(tx, gameID)
+		return err
+	}, gameID)
+	if err != nil {
+		return nil, err
+	}
+	return ruleset, nil
+}
+
+func (s *gameService) GetScoringRulesets(gameID uuid.UUID) ([]models.ScoringRuleset, error) {
+	return s.rulesetRepo.GetByGameID(gameID)
+}
+
+// DiffScoringRulesets lists the action types that were added, removed or
+// re-pointed between two ruleset versions of a game
+func (s *gameService) DiffScoringRulesets(gameID uuid.UUID, fromVersion, toVersion int) ([]models.ScoringRulesetDiffEntry, error) {
+	from, err := s.rulesetRepo.GetByGameIDAndVersion(gameID, fromVersion)
+	if err != nil {
+		return nil, fmt.Errorf("ruleset version %d not found: %w", fromVersion, err)
+	}
+	to, err := s.rulesetRepo.GetByGameIDAndVersion(gameID, toVersion)
+	if err != nil {
+		return nil, fmt.Errorf("ruleset version %d not found: %w", toVersion, err)
+	}
+
+	fromPoints, err := rulesetPointsByAction(from)
+	if err != nil {
+		return nil, err
+	}
+	toPoints, err := rulesetPointsByAction(to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := []models.ScoringRulesetDiffEntry{}
+	for actionType, oldPoints := range fromPoints {
+		oldPoints := oldPoints
+		newPoints, exists := toPoints[actionType]
+		if !exists {
+			diff = append(diff, models.ScoringRulesetDiffEntry{ActionType: actionType, Change: "removed", FromPoints: &oldPoints})
+		} else if newPoints != oldPoints {
+			diff = append(diff, models.ScoringRulesetDiffEntry{ActionType: actionType, Change: "changed", FromPoints: &oldPoints, ToPoints: &newPoints})
+		}
+	}
+	for actionType, newPoints := range toPoints {
+		newPoints := newPoints
+		if _, exists := fromPoints[actionType]; !exists {
+			diff = append(diff, models.ScoringRulesetDiffEntry{ActionType: actionType, Change: "added", ToPoints: &newPoints})
+		}
+	}
+
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].ActionType < diff[j].ActionType
+	})
+
+	return diff, nil
+}
+
+func rulesetPointsByAction(ruleset *models.ScoringRuleset) (map[string]float64, error) {
+	var rules []models.ScoringRulesetRule
+	if err := json.Unmarshal([]byte(ruleset.Rules), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse scoring ruleset v%d: %w", ruleset.Version, err)
+	}
+
+	points := make(map[string]float64)
+	for _, rule := range rules {
+		points[normalizeActionType(rule.ActionType)] += rule.Points
+	}
+	return points, nil
 }
\ No newline at end of file
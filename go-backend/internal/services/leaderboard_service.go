@@ -4,6 +4,8 @@ import (
 	"context"
 	"esports-fantasy-backend/internal/repository"
 	"fmt"
+	"math"
+	"strconv"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
@@ -21,10 +23,12 @@ type LeaderboardService interface {
 type LeaderboardEntry struct {
 	TeamID     uuid.UUID `json:"team_id"`
 	TeamName   string    `json:"team_name"`
+	EntryLabel string    `json:"entry_label"` // T1..Tn among the user's teams in the contest
 	UserID     uuid.UUID `json:"user_id"`
 	UserName   string    `json:"user_name"`
 	Points     float64   `json:"points"`
 	Rank       int       `json:"rank"`
+	IsTied     bool      `json:"is_tied"`
 }
 
 type leaderboardService struct {
@@ -43,8 +47,13 @@ func (s *leaderboardService) GetLeaderboard(contestID uuid.UUID, limit int) ([]L
 	ctx := context.Background()
 	leaderboardKey := fmt.Sprintf("leaderboard:%s", contestID)
 
-	// Get top teams from Redis sorted set
-	results, err := s.rdb.ZRevRangeWithScores(ctx, leaderboardKey, 0, int64(limit-1)).Result()
+	// Get top teams from Redis sorted set, plus one more so a tie across the
+	// cut-off is still flagged
+	stop := int64(limit)
+	if limit <= 0 {
+		stop = -1
+	}
+	results, err := s.rdb.ZRevRangeWithScores(ctx, leaderboardKey, 0, stop).Result()
 	if err != nil {
 		// Fallback to database if Redis fails
 		return s.getLeaderboardFromDB(contestID, limit)
@@ -55,8 +64,14 @@ func (s *leaderboardService) GetLeaderboard(contestID uuid.UUID, limit int) ([]L
 		return s.getLeaderboardFromDB(contestID, limit)
 	}
 
+	ranks, tied := competitionRanks(len(results), func(i int) float64 { return results[i].Score })
+
 	var entries []LeaderboardEntry
 	for i, result := range results {
+		if limit > 0 && i >= limit {
+			break
+		}
+
 		teamIDStr, ok := result.Member.(string)
 		if !ok {
 			continue
@@ -74,12 +89,14 @@ func (s *leaderboardService) GetLeaderboard(contestID uuid.UUID, limit int) ([]L
 		}
 
 		entries = append(entries, LeaderboardEntry{
-			TeamID:   teamID,
-			TeamName: team.TeamName,
-			UserID:   team.User.ID,
-			UserName: team.User.Name,
-			Points:   result.Score,
-			Rank:     i + 1,
+			TeamID:     teamID,
+			TeamName:   team.TeamName,
+			EntryLabel: team.EntryLabel,
+			UserID:     team.User.ID,
+			UserName:   team.User.Name,
+			Points:     result.Score,
+			Rank:       ranks[i],
+			IsTied:     tied[i],
 		})
 	}
 
@@ -87,20 +104,31 @@ func (s *leaderboardService) GetLeaderboard(contestID uuid.UUID, limit int) ([]L
 }
 
 func (s *leaderboardService) getLeaderboardFromDB(contestID uuid.UUID, limit int) ([]LeaderboardEntry, error) {
-	teams, err := s.fantasyTeamRepo.GetLeaderboard(contestID, limit)
+	fetch := limit
+	if limit > 0 {
+		fetch = limit + 1
+	}
+	teams, err := s.fantasyTeamRepo.GetLeaderboard(contestID, fetch)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get leaderboard from database: %w", err)
 	}
 
+	ranks, tied := competitionRanks(len(teams), func(i int) float64 { return teams[i].TotalPoints })
+
 	var entries []LeaderboardEntry
 	for i, team := range teams {
+		if limit > 0 && i >= limit {
+			break
+		}
 		entries = append(entries, LeaderboardEntry{
-			TeamID:   team.ID,
-			TeamName: team.TeamName,
-			UserID:   team.User.ID,
-			UserName: team.User.Name,
-			Points:   team.TotalPoints,
-			Rank:     i + 1,
+			TeamID:     team.ID,
+			TeamName:   team.TeamName,
+			EntryLabel: team.EntryLabel,
+			UserID:     team.User.ID,
+			UserName:   team.User.Name,
+			Points:     team.TotalPoints,
+			Rank:       ranks[i],
+			IsTied:     tied[i],
 		})
 	}
 
@@ -122,8 +150,7 @@ func (s *leaderboardService) GetTeamRank(contestID, teamID uuid.UUID) (int, erro
 	ctx := context.Background()
 	leaderboardKey := fmt.Sprintf("leaderboard:%s", contestID)
 
-	// Get rank from Redis (0-indexed, so add 1)
-	rank, err := s.rdb.ZRevRank(ctx, leaderboardKey, teamID.String()).Result()
+	score, err := s.rdb.ZScore(ctx, leaderboardKey, teamID.String()).Result()
 	if err != nil {
 		if err == redis.Nil {
 			return 0, fmt.Errorf("team not found in leaderboard")
@@ -131,7 +158,14 @@ func (s *leaderboardService) GetTeamRank(contestID, teamID uuid.UUID) (int, erro
 		return 0, err
 	}
 
-	return int(rank) + 1, nil
+	// Teams on the same points share a rank, so count only strictly better scores
+	better := (math.Round(score*100) + 0.5) / 100
+	ahead, err := s.rdb.ZCount(ctx, leaderboardKey, strconv.FormatFloat(better, 'f', -1, 64), "+inf").Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(ahead) + 1, nil
 }
 
 func (s *leaderboardService) InitializeContestLeaderboard(contestID uuid.UUID) error {
@@ -148,27 +182,35 @@ func (s *leaderboardService) GetContestLeaderboard(contestID uuid.UUID, limit in
 	return s.GetLeaderboard(contestID, limit)
 }
 
+// RefreshContestLeaderboard rebuilds the contest's Redis leaderboard from
+// every team in the database. The set is replaced in one MULTI so readers
+// and concurrent score updates never see it empty or half built.
 func (s *leaderboardService) RefreshContestLeaderboard(contestID uuid.UUID) error {
-	// Refresh leaderboard by calculating scores from database
-	entries, err := s.getLeaderboardFromDB(contestID, 100)
+	entries, err := s.getLeaderboardFromDB(contestID, 0)
 	if err != nil {
 		return err
 	}
-	
-	// Update Redis with fresh data
-	ctx := context.Background()
-	leaderboardKey := fmt.Sprintf("leaderboard:%s", contestID)
-	
-	// Clear existing data
-	s.rdb.Del(ctx, leaderboardKey)
-	
-	// Add updated scores
+
+	members := make([]*redis.Z, 0, len(entries))
 	for _, entry := range entries {
-		s.rdb.ZAdd(ctx, leaderboardKey, &redis.Z{
+		members = append(members, &redis.Z{
 			Score:  entry.Points,
 			Member: entry.TeamID.String(),
 		})
 	}
-	
+
+	ctx := context.Background()
+	leaderboardKey := fmt.Sprintf("leaderboard:%s", contestID)
+
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, leaderboardKey)
+		if len(members) > 0 {
+			pipe.ZAdd(ctx, leaderboardKey, members...)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to refresh leaderboard: %w", err)
+	}
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,626 @@
+package services
+
+import (
+	"errors"
+	"esports-fantasy-backend/config"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Ledger account types
+const (
+	AccountUserDeposit     = "user_deposit"     // Money users added, playable only
+	AccountUserWinnings    = "user_winnings"    // Prizes, the only withdrawable funds
+	AccountUserBonus       = "user_bonus"       // Promotional credit users can only play with
+	AccountWithdrawalHold  = "withdrawal_hold"  // Winnings held for a pending withdrawal
+	AccountPlatformRake    = "platform_rake"    // Platform revenue and promotional spend
+	AccountPrizeEscrow     = "prize_escrow"     // Entry fees held until a contest settles
+	AccountGatewayClearing = "gateway_clearing" // Money collected by payment gateways
+)
+
+// Journal entry kinds
+const (
+	JournalKindDeposit         = "deposit"
+	JournalKindContestEntry    = "contest_entry"
+	JournalKindLeagueEntry     = "league_entry"
+	JournalKindPrize           = "prize"
+	JournalKindPrizeCorrection = "prize_correction"
+	JournalKindReferralBonus   = "referral_bonus"
+	JournalKindOpeningBalance  = "opening_balance"
+	JournalKindWithdrawal      = "withdrawal"
+	JournalKindDepositRefund   = "deposit_refund"
+	JournalKindContestRefund   = "contest_refund"
+)
+
+var (
+	ErrUnbalancedEntry     = errors.New("journal entry is not balanced")
+	ErrInsufficientFunds   = errors.New("insufficient wallet balance")
+	ErrDuplicateJournalKey = errors.New("journal entry already posted")
+)
+
+// debitNormalAccounts grow with debits; every other account grows with credits
+var debitNormalAccounts = map[string]bool{
+	AccountGatewayClearing: true,
+}
+
+// userBalanceColumns maps user accounts to the cached bucket on users
+var userBalanceColumns = map[string]string{
+	AccountUserDeposit:  "deposit_balance",
+	AccountUserWinnings: "winnings_balance",
+	AccountUserBonus:    "bonus_balance",
+}
+
+// LedgerAccountRef names an account without needing its ID
+type LedgerAccountRef struct {
+	Type   string
+	UserID *uuid.UUID
+}
+
+func UserDepositAccount(userID uuid.UUID) LedgerAccountRef {
+	return LedgerAccountRef{Type: AccountUserDeposit, UserID: &userID}
+}
+
+func UserWinningsAccount(userID uuid.UUID) LedgerAccountRef {
+	return LedgerAccountRef{Type: AccountUserWinnings, UserID: &userID}
+}
+
+func UserBonusAccount(userID uuid.UUID) LedgerAccountRef {
+	return LedgerAccountRef{Type: AccountUserBonus, UserID: &userID}
+}
+
+func WithdrawalHoldAccount(userID uuid.UUID) LedgerAccountRef {
+	return LedgerAccountRef{Type: AccountWithdrawalHold, UserID: &userID}
+}
+
+func PlatformRakeAccount() LedgerAccountRef {
+	return LedgerAccountRef{Type: AccountPlatformRake}
+}
+
+func PrizeEscrowAccount() LedgerAccountRef {
+	return LedgerAccountRef{Type: AccountPrizeEscrow}
+}
+
+func GatewayClearingAccount() LedgerAccountRef {
+	return LedgerAccountRef{Type: AccountGatewayClearing}
+}
+
+// Code returns the unique account code
+func (r LedgerAccountRef) Code() string {
+	if r.UserID != nil {
+		return fmt.Sprintf("%s:%s", r.Type, r.UserID)
+	}
+	return r.Type
+}
+
+// LedgerPosting is one debit or credit in a journal entry request
+type LedgerPosting struct {
+	Account LedgerAccountRef
+	Debit   float64
+	Credit  float64
+}
+
+func Debit(account LedgerAccountRef, amount float64) LedgerPosting {
+	return LedgerPosting{Account: account, Debit: amount}
+}
+
+func Credit(account LedgerAccountRef, amount float64) LedgerPosting {
+	return LedgerPosting{Account: account, Credit: amount}
+}
+
+// JournalEntryRequest describes a journal entry to post
+type JournalEntryRequest struct {
+	Kind           string
+	ReferenceID    *uuid.UUID
+	TransactionID  *uuid.UUID
+	IdempotencyKey string
+	Description    string
+	Postings       []LedgerPosting
+}
+
+// UserLedgerBalance - A user's wallet buckets as derived from the journal
+type UserLedgerBalance struct {
+	UserID        uuid.UUID     `json:"user_id"`
+	Journal       WalletBuckets `json:"journal"`
+	Cached        WalletBuckets `json:"cached"`
+	Total         float64       `json:"total"`
+	CachedBalance float64       `json:"cached_balance"`
+	Withdrawable  float64       `json:"withdrawable"`
+	InSync        bool          `json:"in_sync"`
+}
+
+// LedgerDiscrepancy - User whose cached wallet balances differ from the journal
+type LedgerDiscrepancy struct {
+	UserID          uuid.UUID `json:"user_id"`
+	CachedBalance   float64   `json:"cached_balance"`
+	JournalBalance  float64   `json:"journal_balance"`
+	Difference      float64   `json:"difference"`
+	CachedDeposit   float64   `json:"cached_deposit"`
+	JournalDeposit  float64   `json:"journal_deposit"`
+	CachedWinnings  float64   `json:"cached_winnings"`
+	JournalWinnings float64   `json:"journal_winnings"`
+	CachedBonus     float64   `json:"cached_bonus"`
+	JournalBonus    float64   `json:"journal_bonus"`
+}
+
+// LedgerAccountMismatch - Account whose cached balance differs from its lines
+type LedgerAccountMismatch struct {
+	AccountID      uuid.UUID `json:"account_id"`
+	Code           string    `json:"code"`
+	CachedBalance  float64   `json:"cached_balance"`
+	JournalBalance float64   `json:"journal_balance"`
+}
+
+// LedgerReconciliation - Result of checking the ledger against cached balances
+type LedgerReconciliation struct {
+	CheckedAt         time.Time               `json:"checked_at"`
+	UsersChecked      int64                   `json:"users_checked"`
+	UnbalancedEntries []uuid.UUID             `json:"unbalanced_entries"`
+	AccountMismatches []LedgerAccountMismatch `json:"account_mismatches"`
+	UserDiscrepancies []LedgerDiscrepancy     `json:"user_discrepancies"`
+}
+
+// Clean reports whether reconciliation found nothing to fix
+func (r *LedgerReconciliation) Clean() bool {
+	return len(r.UnbalancedEntries) == 0 && len(r.AccountMismatches) == 0 && len(r.UserDiscrepancies) == 0
+}
+
+type LedgerService interface {
+	Post(tx *gorm.DB, req *JournalEntryRequest) (*models.JournalEntry, error)
+	RecordDeposit(tx *gorm.DB, userID uuid.UUID, amount float64, transactionID *uuid.UUID, idempotencyKey string) error
+	RecordEntryFee(tx *gorm.DB, kind string, userID uuid.UUID, amount float64, referenceID uuid.UUID, transactionID *uuid.UUID, idempotencyKey string) (WalletBuckets, error)
+	RecordBonus(tx *gorm.DB, userID uuid.UUID, amount float64, idempotencyKey, description string) error
+	EntryFeeShortfall(userID uuid.UUID, amount float64) (float64, error)
+	EscrowBalance(tx *gorm.DB, referenceID uuid.UUID) (float64, error)
+	GetUserBalance(userID uuid.UUID) (*UserLedgerBalance, error)
+	Reconcile() (*LedgerReconciliation, error)
+	BackfillOpeningBalances() (int, error)
+}
+
+type ledgerService struct {
+	db            *gorm.DB
+	entryFeeRules EntryFeeRules
+}
+
+func NewLedgerService(db *gorm.DB, cfg *config.Config) LedgerService {
+	return &ledgerService{
+		db:            db,
+		entryFeeRules: EntryFeeRulesFromConfig(cfg),
+	}
+}
+
+// inTx runs fn in the caller's transaction, or in a new one when tx is nil
+func (s *ledgerService) inTx(tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if tx != nil {
+		return fn(tx)
+	}
+	return s.db.Transaction(fn)
+}
+
+// Post validates and records a balanced journal entry, updating the cached
+// account balances and users' bucket and wallet balances in the same
+// transaction.
+// Posting an idempotency key that already exists returns the earlier entry.
+func (s *ledgerService) Post(tx *gorm.DB, req *JournalEntryRequest) (*models.JournalEntry, error) {
+	if err := validatePostings(req.Postings); err != nil {
+		return nil, err
+	}
+
+	var entry models.JournalEntry
+	err := s.inTx(tx, func(tx *gorm.DB) error {
+		if req.IdempotencyKey != "" {
+			err := tx.Preload("Lines").First(&entry, "idempotency_key = ?", req.IdempotencyKey).Error
+			if err == nil {
+				return ErrDuplicateJournalKey
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("failed to check journal entry: %w", err)
+			}
+		}
+
+		entry = models.JournalEntry{
+			Kind:          req.Kind,
+			ReferenceID:   req.ReferenceID,
+			TransactionID: req.TransactionID,
+			Description:   req.Description,
+		}
+		if req.IdempotencyKey != "" {
+			key := req.IdempotencyKey
+			entry.IdempotencyKey = &key
+		}
+
+		walletDeltas := make(map[uuid.UUID]map[string]float64)
+		for _, posting := range req.Postings {
+			account, err := s.account(tx, posting.Account)
+			if err != nil {
+				return err
+			}
+
+			entry.Lines = append(entry.Lines, models.JournalLine{
+				AccountID: account.ID,
+				Debit:     roundCurrency(posting.Debit),
+				Credit:    roundCurrency(posting.Credit),
+			})
+
+			change := posting.Credit - posting.Debit
+			if debitNormalAccounts[account.Type] {
+				change = -change
+			}
+			err = tx.Model(&models.LedgerAccount{}).Where("id = ?", account.ID).
+				Update("balance", gorm.Expr("balance + ?", roundCurrency(change))).Error
+			if err != nil {
+				return fmt.Errorf("failed to update account %s: %w", account.Code, err)
+			}
+
+			if column, ok := userBalanceColumns[account.Type]; ok && account.UserID != nil {
+				if walletDeltas[*account.UserID] == nil {
+					walletDeltas[*account.UserID] = make(map[string]float64)
+				}
+				walletDeltas[*account.UserID][column] += posting.Credit - posting.Debit
+			}
+		}
+
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("failed to post journal entry: %w", err)
+		}
+
+		for userID, deltas := range walletDeltas {
+			if err := addToCachedBalances(tx, userID, deltas); err != nil {
+				return fmt.Errorf("failed to update wallet: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if errors.Is(err, ErrDuplicateJournalKey) {
+		return &entry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func validatePostings(postings []LedgerPosting) error {
+	if len(postings) < 2 {
+		return fmt.Errorf("%w: at least two postings are required", ErrUnbalancedEntry)
+	}
+
+	debits, credits := 0.0, 0.0
+	for _, posting := range postings {
+		if posting.Debit < 0 || posting.Credit < 0 || (posting.Debit > 0) == (posting.Credit > 0) {
+			return fmt.Errorf("%w: each posting needs a positive debit or credit", ErrUnbalancedEntry)
+		}
+		debits += roundCurrency(posting.Debit)
+		credits += roundCurrency(posting.Credit)
+	}
+
+	if math.Abs(debits-credits) >= 0.005 {
+		return fmt.Errorf("%w: debits %.2f, credits %.2f", ErrUnbalancedEntry, debits, credits)
+	}
+	return nil
+}
+
+// account returns the ledger account for ref, opening it on first use
+func (s *ledgerService) account(tx *gorm.DB, ref LedgerAccountRef) (*models.LedgerAccount, error) {
+	account := models.LedgerAccount{
+		Code:   ref.Code(),
+		Type:   ref.Type,
+		UserID: ref.UserID,
+	}
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&account).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to open account %s: %w", account.Code, err)
+	}
+	if err := tx.First(&account, "code = ?", account.Code).Error; err != nil {
+		return nil, fmt.Errorf("failed to get account %s: %w", account.Code, err)
+	}
+	return &account, nil
+}
+
+// RecordDeposit credits a user's deposit bucket with money collected by a
+// gateway
+func (s *ledgerService) RecordDeposit(tx *gorm.DB, userID uuid.UUID, amount float64, transactionID *uuid.UUID, idempotencyKey string) error {
+	_, err := s.Post(tx, &JournalEntryRequest{
+		Kind:           JournalKindDeposit,
+		TransactionID:  transactionID,
+		IdempotencyKey: idempotencyKey,
+		Description:    "Wallet deposit",
+		Postings: []LedgerPosting{
+			Debit(GatewayClearingAccount(), amount),
+			Credit(UserDepositAccount(userID), amount),
+		},
+	})
+	return err
+}
+
+// RecordEntryFee moves an entry fee from the user's wallet into prize escrow,
+// drawing on buckets as the entry fee rules allow, and returns how much each
+// bucket paid. The user row is locked so concurrent entries cannot overdraw
+// the wallet.
+func (s *ledgerService) RecordEntryFee(tx *gorm.DB, kind string, userID uuid.UUID, amount float64, referenceID uuid.UUID, transactionID *uuid.UUID, idempotencyKey string) (WalletBuckets, error) {
+	var split WalletBuckets
+	err := s.inTx(tx, func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error; err != nil {
+			return fmt.Errorf("user not found: %w", err)
+		}
+
+		var err error
+		split, err = s.entryFeeRules.Split(amount, WalletBuckets{
+			Deposit:  user.DepositBalance,
+			Winnings: user.WinningsBalance,
+			Bonus:    user.BonusBalance,
+		})
+		if err != nil {
+			return err
+		}
+
+		postings := []LedgerPosting{Credit(PrizeEscrowAccount(), amount)}
+		for _, bucket := range []string{WalletBucketDeposit, WalletBucketWinnings, WalletBucketBonus} {
+			if paid := split.get(bucket); paid > 0 {
+				postings = append(postings, Debit(userBucketAccount(bucket, userID), paid))
+			}
+		}
+
+		_, err = s.Post(tx, &JournalEntryRequest{
+			Kind:           kind,
+			ReferenceID:    &referenceID,
+			TransactionID:  transactionID,
+			IdempotencyKey: idempotencyKey,
+			Description:    "Entry fee",
+			Postings:       postings,
+		})
+		return err
+	})
+	if err != nil {
+		return WalletBuckets{}, err
+	}
+	return split, nil
+}
+
+// EntryFeeShortfall returns how much the user must deposit before their
+// wallet can pay an entry fee, or 0 if it already can
+func (s *ledgerService) EntryFeeShortfall(userID uuid.UUID, amount float64) (float64, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return 0, fmt.Errorf("user not found: %w", err)
+	}
+
+	return s.entryFeeRules.Shortfall(amount, WalletBuckets{
+		Deposit:  user.DepositBalance,
+		Winnings: user.WinningsBalance,
+		Bonus:    user.BonusBalance,
+	}), nil
+}
+
+// RecordBonus grants promotional credit paid for by the platform
+func (s *ledgerService) RecordBonus(tx *gorm.DB, userID uuid.UUID, amount float64, idempotencyKey, description string) error {
+	_, err := s.Post(tx, &JournalEntryRequest{
+		Kind:           JournalKindReferralBonus,
+		IdempotencyKey: idempotencyKey,
+		Description:    description,
+		Postings: []LedgerPosting{
+			Debit(PlatformRakeAccount(), amount),
+			Credit(UserBonusAccount(userID), amount),
+		},
+	})
+	return err
+}
+
+// EscrowBalance returns the entry fees held in escrow for a contest or league
+func (s *ledgerService) EscrowBalance(tx *gorm.DB, referenceID uuid.UUID) (float64, error) {
+	if tx == nil {
+		tx = s.db
+	}
+
+	var balance float64
+	err := tx.Model(&models.JournalLine{}).
+		Select("COALESCE(SUM(journal_lines.credit - journal_lines.debit), 0)").
+		Joins("JOIN journal_entries ON journal_entries.id = journal_lines.journal_entry_id").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = journal_lines.account_id").
+		Where("ledger_accounts.type = ? AND journal_entries.reference_id = ?", AccountPrizeEscrow, referenceID).
+		Scan(&balance).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get escrow balance: %w", err)
+	}
+	return roundCurrency(balance), nil
+}
+
+// accountBalance derives an account's balance from its journal lines
+func (s *ledgerService) accountBalance(tx *gorm.DB, ref LedgerAccountRef) (float64, error) {
+	var balance float64
+	err := tx.Model(&models.JournalLine{}).
+		Select("COALESCE(SUM(journal_lines.credit - journal_lines.debit), 0)").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = journal_lines.account_id").
+		Where("ledger_accounts.code = ?", ref.Code()).
+		Scan(&balance).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get balance of %s: %w", ref.Code(), err)
+	}
+	if debitNormalAccounts[ref.Type] {
+		balance = -balance
+	}
+	return roundCurrency(balance), nil
+}
+
+func (s *ledgerService) GetUserBalance(userID uuid.UUID) (*UserLedgerBalance, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	var journal WalletBuckets
+	for _, bucket := range []string{WalletBucketDeposit, WalletBucketWinnings, WalletBucketBonus} {
+		balance, err := s.accountBalance(s.db, userBucketAccount(bucket, userID))
+		if err != nil {
+			return nil, err
+		}
+		journal.add(bucket, balance)
+	}
+
+	cached := WalletBuckets{
+		Deposit:  user.DepositBalance,
+		Winnings: user.WinningsBalance,
+		Bonus:    user.BonusBalance,
+	}
+
+	return &UserLedgerBalance{
+		UserID:        userID,
+		Journal:       journal,
+		Cached:        cached,
+		Total:         journal.Total(),
+		CachedBalance: user.WalletBalance,
+		Withdrawable:  math.Max(journal.Winnings, 0),
+		InSync:        journal == cached && roundCurrency(user.WalletBalance) == journal.Total(),
+	}, nil
+}
+
+// Reconcile checks that every journal entry balances, that cached account
+// balances match their lines, and that every user's cached wallet and bucket
+// balances match their user accounts
+func (s *ledgerService) Reconcile() (*LedgerReconciliation, error) {
+	result := &LedgerReconciliation{CheckedAt: time.Now()}
+
+	err := s.db.Model(&models.JournalLine{}).
+		Select("journal_entry_id").
+		Group("journal_entry_id").
+		Having("ABS(SUM(debit) - SUM(credit)) >= 0.005").
+		Scan(&result.UnbalancedEntries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check journal entries: %w", err)
+	}
+
+	err = s.db.Table("ledger_accounts").
+		Select(`ledger_accounts.id AS account_id, ledger_accounts.code, ledger_accounts.balance AS cached_balance,
+			COALESCE(SUM(CASE WHEN ledger_accounts.type = ? THEN journal_lines.debit - journal_lines.credit
+				ELSE journal_lines.credit - journal_lines.debit END), 0) AS journal_balance`, AccountGatewayClearing).
+		Joins("LEFT JOIN journal_lines ON journal_lines.account_id = ledger_accounts.id").
+		Group("ledger_accounts.id, ledger_accounts.code, ledger_accounts.balance").
+		Having("ABS(ledger_accounts.balance - COALESCE(SUM(CASE WHEN ledger_accounts.type = ? THEN journal_lines.debit - journal_lines.credit ELSE journal_lines.credit - journal_lines.debit END), 0)) >= 0.005", AccountGatewayClearing).
+		Scan(&result.AccountMismatches).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check account balances: %w", err)
+	}
+
+	if err := s.db.Model(&models.User{}).Count(&result.UsersChecked).Error; err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	mismatch := func(cached, journal string) string {
+		return fmt.Sprintf("ABS(%s - COALESCE(%s, 0)) >= 0.005", cached, journal)
+	}
+	err = s.db.Table("users").
+		Select(`users.id AS user_id,
+			users.wallet_balance AS cached_balance,
+			COALESCE(journal.deposit + journal.winnings + journal.bonus, 0) AS journal_balance,
+			users.wallet_balance - COALESCE(journal.deposit + journal.winnings + journal.bonus, 0) AS difference,
+			users.deposit_balance AS cached_deposit, COALESCE(journal.deposit, 0) AS journal_deposit,
+			users.winnings_balance AS cached_winnings, COALESCE(journal.winnings, 0) AS journal_winnings,
+			users.bonus_balance AS cached_bonus, COALESCE(journal.bonus, 0) AS journal_bonus`).
+		Joins(`LEFT JOIN (
+			SELECT ledger_accounts.user_id,
+				SUM(CASE WHEN ledger_accounts.type = ? THEN journal_lines.credit - journal_lines.debit ELSE 0 END) AS deposit,
+				SUM(CASE WHEN ledger_accounts.type = ? THEN journal_lines.credit - journal_lines.debit ELSE 0 END) AS winnings,
+				SUM(CASE WHEN ledger_accounts.type = ? THEN journal_lines.credit - journal_lines.debit ELSE 0 END) AS bonus
+			FROM journal_lines JOIN ledger_accounts ON ledger_accounts.id = journal_lines.account_id
+			WHERE ledger_accounts.user_id IS NOT NULL
+			GROUP BY ledger_accounts.user_id
+		) journal ON journal.user_id = users.id`, AccountUserDeposit, AccountUserWinnings, AccountUserBonus).
+		Where("users.deleted_at IS NULL").
+		Where(mismatch("users.wallet_balance", "journal.deposit + journal.winnings + journal.bonus") + " OR " +
+			mismatch("users.deposit_balance", "journal.deposit") + " OR " +
+			mismatch("users.winnings_balance", "journal.winnings") + " OR " +
+			mismatch("users.bonus_balance", "journal.bonus")).
+		Scan(&result.UserDiscrepancies).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check user balances: %w", err)
+	}
+
+	return result, nil
+}
+
+// addToCachedBalances adds the posted changes to a user's cached balances.
+// The columns are create-only for GORM so no other code can overwrite them,
+// which means they can only be written with raw SQL.
+func addToCachedBalances(tx *gorm.DB, userID uuid.UUID, deltas map[string]float64) error {
+	columns := make([]string, 0, len(deltas))
+	for column := range deltas {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	total := 0.0
+	sets := make([]string, 0, len(columns)+2)
+	args := make([]interface{}, 0, len(columns)+3)
+	for _, column := range columns {
+		total += deltas[column]
+		sets = append(sets, column+" = "+column+" + ?")
+		args = append(args, roundCurrency(deltas[column]))
+	}
+	sets = append(sets, "wallet_balance = wallet_balance + ?", "updated_at = ?")
+	args = append(args, roundCurrency(total), time.Now(), userID)
+
+	return tx.Exec("UPDATE users SET "+strings.Join(sets, ", ")+" WHERE id = ?", args...).Error
+}
+
+// BackfillOpeningBalances posts an opening balance for every user whose
+// wallet predates the ledger, so the journal accounts for their funds. Users
+// who already have journal lines are left for reconciliation to report.
+func (s *ledgerService) BackfillOpeningBalances() (int, error) {
+	var users []models.User
+	err := s.db.Where("wallet_balance <> 0").
+		Where("NOT EXISTS (SELECT 1 FROM ledger_accounts WHERE ledger_accounts.user_id = users.id)").
+		Find(&users).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	posted := 0
+	for _, user := range users {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			// Posting adds to the cached balances, so clear them first
+			err := tx.Exec(`UPDATE users SET wallet_balance = 0, deposit_balance = 0, winnings_balance = 0, bonus_balance = 0
+				WHERE id = ?`, user.ID).Error
+			if err != nil {
+				return err
+			}
+
+			// Pre-ledger money cannot be told apart, so none of it is withdrawable
+			postings := []LedgerPosting{
+				Debit(GatewayClearingAccount(), user.WalletBalance),
+				Credit(UserDepositAccount(user.ID), user.WalletBalance),
+			}
+			if user.WalletBalance < 0 {
+				postings = []LedgerPosting{
+					Debit(UserDepositAccount(user.ID), -user.WalletBalance),
+					Credit(GatewayClearingAccount(), -user.WalletBalance),
+				}
+			}
+
+			_, err = s.Post(tx, &JournalEntryRequest{
+				Kind:           JournalKindOpeningBalance,
+				IdempotencyKey: fmt.Sprintf("opening_balance:%s", user.ID),
+				Description:    "Wallet balance before the ledger",
+				Postings:       postings,
+			})
+			return err
+		})
+		if err != nil {
+			return posted, fmt.Errorf("failed to backfill user %s: %w", user.ID, err)
+		}
+		posted++
+	}
+
+	log.Printf("📒 Posted opening balances for %d users", posted)
+	return posted, nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"esports-fantasy-backend/config"
+	"esports-fantasy-backend/internal/models"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// testDB connects to the Postgres database in TEST_DATABASE_URL and runs the
+// test in a transaction that is rolled back afterwards
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.LedgerAccount{}, &models.JournalEntry{}, &models.JournalLine{}); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+
+	tx := db.Begin()
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+func TestPostUpdatesCachedBalances(t *testing.T) {
+	tx := testDB(t)
+	ledger := NewLedgerService(tx, &config.Config{})
+
+	suffix := uuid.NewString()[:8]
+	user := models.User{
+		ID:           uuid.New(),
+		PhoneNumber:  "+91" + suffix,
+		Username:     "ledger_" + suffix,
+		ReferralCode: "LEDGER" + suffix,
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	_, err := ledger.Post(tx, &JournalEntryRequest{
+		Kind:           JournalKindDeposit,
+		IdempotencyKey: "test_post:" + user.ID.String(),
+		Description:    "Test posting",
+		Postings: []LedgerPosting{
+			Debit(GatewayClearingAccount(), 175),
+			Credit(UserDepositAccount(user.ID), 100),
+			Credit(UserWinningsAccount(user.ID), 50),
+			Credit(UserBonusAccount(user.ID), 25),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to post: %v", err)
+	}
+
+	checkBalances := func(when string) {
+		t.Helper()
+		var got models.User
+		if err := tx.First(&got, "id = ?", user.ID).Error; err != nil {
+			t.Fatalf("failed to read user %s: %v", when, err)
+		}
+		for _, c := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"wallet_balance", got.WalletBalance, 175},
+			{"deposit_balance", got.DepositBalance, 100},
+			{"winnings_balance", got.WinningsBalance, 50},
+			{"bonus_balance", got.BonusBalance, 25},
+		} {
+			if math.Abs(c.got-c.want) > 0.001 {
+				t.Errorf("%s %s = %.2f, want %.2f", when, c.name, c.got, c.want)
+			}
+		}
+	}
+	checkBalances("after posting")
+
+	// Saving a stale user must not overwrite the cached balances
+	var stale models.User
+	if err := tx.First(&stale, "id = ?", user.ID).Error; err != nil {
+		t.Fatalf("failed to read user: %v", err)
+	}
+	stale.WalletBalance, stale.DepositBalance, stale.WinningsBalance, stale.BonusBalance = 0, 0, 0, 0
+	stale.Name = "Renamed"
+	if err := tx.Save(&stale).Error; err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+	checkBalances("after saving a stale user")
+}
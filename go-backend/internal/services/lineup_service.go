@@ -0,0 +1,271 @@
+package services
+
+import (
+	"errors"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLineupNotFound   = errors.New("saved lineup not found")
+	ErrLineupWrongMatch = errors.New("contest is not on the lineup's match")
+)
+
+// LineupEntryResult - What happened to one contest entry when a saved lineup
+// was joined or edited
+type LineupEntryResult struct {
+	ContestID     uuid.UUID            `json:"contest_id"`
+	FantasyTeamID *uuid.UUID           `json:"fantasy_team_id,omitempty"`
+	Entry         *models.ContestEntry `json:"entry,omitempty"`
+	Error         string               `json:"error,omitempty"`
+}
+
+// LineupUpdateResult - A saved lineup after an edit, with the entries that
+// took the change and those that could not
+type LineupUpdateResult struct {
+	Lineup  *models.SavedLineup `json:"lineup"`
+	Updated []LineupEntryResult `json:"updated"`
+	Skipped []LineupEntryResult `json:"skipped"`
+}
+
+// LineupService keeps users' saved lineups for a match. A lineup can be
+// entered into any of the match's contests, each entry being a team of its
+// own; editing the lineup updates every entry still following it whose
+// contest has not locked.
+type LineupService interface {
+	CreateLineup(userID uuid.UUID, req *models.SaveLineupRequest) (*models.SavedLineup, error)
+	GetUserLineups(userID uuid.UUID, matchID *uuid.UUID) ([]models.SavedLineup, error)
+	GetLineup(userID, lineupID uuid.UUID) (*models.SavedLineup, error)
+	UpdateLineup(userID, lineupID uuid.UUID, req *models.UpdateLineupRequest) (*LineupUpdateResult, error)
+	CloneLineup(userID, lineupID uuid.UUID, req *models.CloneLineupRequest) (*models.SavedLineup, error)
+	JoinContests(userID, lineupID uuid.UUID, req *models.JoinWithLineupRequest) ([]LineupEntryResult, error)
+}
+
+type lineupService struct {
+	db                 *gorm.DB
+	fantasyTeamService FantasyTeamService
+	contestJoinService ContestJoinService
+}
+
+func NewLineupService(db *gorm.DB, fantasyTeamService FantasyTeamService, contestJoinService ContestJoinService) LineupService {
+	return &lineupService{
+		db:                 db,
+		fantasyTeamService: fantasyTeamService,
+		contestJoinService: contestJoinService,
+	}
+}
+
+func lineupPlayers(lineupID uuid.UUID, playerIDs []uuid.UUID, captainID, viceCaptainID uuid.UUID) []models.SavedLineupPlayer {
+	players := make([]models.SavedLineupPlayer, 0, len(playerIDs))
+	for _, playerID := range playerIDs {
+		players = append(players, models.SavedLineupPlayer{
+			SavedLineupID: lineupID,
+			PlayerID:      playerID,
+			IsCaptain:     playerID == captainID,
+			IsViceCaptain: playerID == viceCaptainID,
+		})
+	}
+	return players
+}
+
+// savedLineupPicks reads back the players, captain and vice-captain of a lineup
+func savedLineupPicks(lineup *models.SavedLineup) (playerIDs []uuid.UUID, captainID, viceCaptainID uuid.UUID) {
+	for _, player := range lineup.Players {
+		playerIDs = append(playerIDs, player.PlayerID)
+		if player.IsCaptain {
+			captainID = player.PlayerID
+		}
+		if player.IsViceCaptain {
+			viceCaptainID = player.PlayerID
+		}
+	}
+	return playerIDs, captainID, viceCaptainID
+}
+
+func (s *lineupService) CreateLineup(userID uuid.UUID, req *models.SaveLineupRequest) (*models.SavedLineup, error) {
+	if err := s.fantasyTeamService.ValidateLineup(req.MatchID, req.PlayerIDs, req.CaptainID, req.ViceCaptainID); err != nil {
+		return nil, err
+	}
+
+	lineup := &models.SavedLineup{
+		ID:      uuid.New(),
+		UserID:  userID,
+		MatchID: req.MatchID,
+		Name:    req.Name,
+	}
+	lineup.Players = lineupPlayers(lineup.ID, req.PlayerIDs, req.CaptainID, req.ViceCaptainID)
+
+	// Creating the lineup also creates its player rows
+	if err := s.db.Create(lineup).Error; err != nil {
+		return nil, fmt.Errorf("failed to save lineup: %w", err)
+	}
+	return s.GetLineup(userID, lineup.ID)
+}
+
+// GetUserLineups lists a user's saved lineups, newest first, optionally for one match
+func (s *lineupService) GetUserLineups(userID uuid.UUID, matchID *uuid.UUID) ([]models.SavedLineup, error) {
+	query := s.db.Preload("Players").Preload("Players.Player").Where("user_id = ?", userID)
+	if matchID != nil {
+		query = query.Where("match_id = ?", *matchID)
+	}
+
+	var lineups []models.SavedLineup
+	if err := query.Order("created_at DESC").Find(&lineups).Error; err != nil {
+		return nil, fmt.Errorf("failed to get lineups: %w", err)
+	}
+	return lineups, nil
+}
+
+func (s *lineupService) GetLineup(userID, lineupID uuid.UUID) (*models.SavedLineup, error) {
+	var lineup models.SavedLineup
+	err := s.db.Preload("Players").Preload("Players.Player").
+		First(&lineup, "id = ? AND user_id = ?", lineupID, userID).Error
+	if err != nil {
+		return nil, ErrLineupNotFound
+	}
+	return &lineup, nil
+}
+
+// UpdateLineup saves the new lineup, then applies it to each entry following
+// it in a contest that is still open. An entry that cannot take the change,
+// such as one whose contest locks meanwhile or where the user already has a
+// team with the new lineup, keeps its old lineup and is reported as skipped.
+func (s *lineupService) UpdateLineup(userID, lineupID uuid.UUID, req *models.UpdateLineupRequest) (*LineupUpdateResult, error) {
+	lineup, err := s.GetLineup(userID, lineupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fantasyTeamService.ValidateLineup(lineup.MatchID, req.PlayerIDs, req.CaptainID, req.ViceCaptainID); err != nil {
+		return nil, err
+	}
+
+	playerIDs, captainID, viceCaptainID := savedLineupPicks(lineup)
+	changed := lineupKey(playerIDs, captainID, viceCaptainID) != lineupKey(req.PlayerIDs, req.CaptainID, req.ViceCaptainID)
+
+	name := req.Name
+	if name == "" {
+		name = lineup.Name
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("saved_lineup_id = ?", lineup.ID).Delete(&models.SavedLineupPlayer{}).Error; err != nil {
+			return fmt.Errorf("failed to remove old players: %w", err)
+		}
+		if err := tx.Create(lineupPlayers(lineup.ID, req.PlayerIDs, req.CaptainID, req.ViceCaptainID)).Error; err != nil {
+			return fmt.Errorf("failed to save players: %w", err)
+		}
+		return tx.Model(lineup).Update("name", name).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update lineup: %w", err)
+	}
+
+	result := &LineupUpdateResult{Updated: []LineupEntryResult{}, Skipped: []LineupEntryResult{}}
+	if changed {
+		// Entries in locked contests keep the lineup they locked with
+		var teams []models.FantasyTeam
+		err := s.db.Joins("JOIN contests ON contests.id = fantasy_teams.contest_id").
+			Where("fantasy_teams.saved_lineup_id = ? AND fantasy_teams.user_id = ?", lineup.ID, userID).
+			Where("contests.locked_at IS NULL AND LOWER(contests.status) = ?", ContestStatusOpen).
+			Find(&teams).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lineup entries: %w", err)
+		}
+
+		for _, team := range teams {
+			teamID := team.ID
+			entry := LineupEntryResult{ContestID: team.ContestID, FantasyTeamID: &teamID}
+			_, err := s.fantasyTeamService.ApplySavedLineup(userID, team.ID, lineup.ID, &models.UpdateTeamRequest{
+				PlayerIDs:     req.PlayerIDs,
+				CaptainID:     req.CaptainID,
+				ViceCaptainID: req.ViceCaptainID,
+			})
+			if err != nil {
+				entry.Error = err.Error()
+				result.Skipped = append(result.Skipped, entry)
+				continue
+			}
+			result.Updated = append(result.Updated, entry)
+		}
+		log.Printf("📝 Lineup %s updated: %d entries updated, %d skipped", lineup.ID, len(result.Updated), len(result.Skipped))
+	}
+
+	result.Lineup, err = s.GetLineup(userID, lineup.ID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CloneLineup copies a lineup into a new one that no entries follow yet
+func (s *lineupService) CloneLineup(userID, lineupID uuid.UUID, req *models.CloneLineupRequest) (*models.SavedLineup, error) {
+	lineup, err := s.GetLineup(userID, lineupID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = lineup.Name + " (copy)"
+	}
+
+	playerIDs, captainID, viceCaptainID := savedLineupPicks(lineup)
+	clone := &models.SavedLineup{
+		ID:      uuid.New(),
+		UserID:  userID,
+		MatchID: lineup.MatchID,
+		Name:    name,
+	}
+	clone.Players = lineupPlayers(clone.ID, playerIDs, captainID, viceCaptainID)
+
+	if err := s.db.Create(clone).Error; err != nil {
+		return nil, fmt.Errorf("failed to clone lineup: %w", err)
+	}
+	return s.GetLineup(userID, clone.ID)
+}
+
+// JoinContests enters the lineup into each contest as a new team, paying each
+// entry fee separately. A contest that cannot be joined does not stop the
+// others; its result carries the error.
+func (s *lineupService) JoinContests(userID, lineupID uuid.UUID, req *models.JoinWithLineupRequest) ([]LineupEntryResult, error) {
+	lineup, err := s.GetLineup(userID, lineupID)
+	if err != nil {
+		return nil, err
+	}
+	playerIDs, captainID, viceCaptainID := savedLineupPicks(lineup)
+
+	results := make([]LineupEntryResult, 0, len(req.ContestIDs))
+	for _, contestID := range req.ContestIDs {
+		result := LineupEntryResult{ContestID: contestID}
+
+		var contest models.Contest
+		switch err := s.db.First(&contest, "id = ?", contestID).Error; {
+		case err != nil:
+			result.Error = fmt.Sprintf("contest not found: %v", err)
+		case contest.MatchID != lineup.MatchID:
+			result.Error = ErrLineupWrongMatch.Error()
+		default:
+			entry, err := s.contestJoinService.JoinContest(userID, &models.CreateTeamRequest{
+				ContestID:     contestID,
+				TeamName:      lineup.Name,
+				PlayerIDs:     playerIDs,
+				CaptainID:     captainID,
+				ViceCaptainID: viceCaptainID,
+				SavedLineupID: &lineup.ID,
+			})
+			if err != nil {
+				result.Error = err.Error()
+				break
+			}
+			result.FantasyTeamID = &entry.FantasyTeamID
+			result.Entry = entry
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
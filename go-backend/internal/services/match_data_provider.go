@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MatchEventSink receives stat events from a match data provider
+type MatchEventSink func(event *models.LiveMatchUpdateRequest) error
+
+// MatchDataProvider is a source of live stat events for a match. Push
+// providers call the sink as events arrive; poll-based sources can be
+// wrapped with NewPollingMatchDataProvider. Start blocks until the feed
+// ends or ctx is cancelled.
+type MatchDataProvider interface {
+	Name() string
+	Start(ctx context.Context, matchID uuid.UUID, sink MatchEventSink) error
+}
+
+// MatchDataPoller fetches the events that happened after the given time
+type MatchDataPoller interface {
+	Name() string
+	Poll(ctx context.Context, matchID uuid.UUID, since time.Time) ([]models.LiveMatchUpdateRequest, error)
+}
+
+type pollingMatchDataProvider struct {
+	poller   MatchDataPoller
+	interval time.Duration
+}
+
+// NewPollingMatchDataProvider turns a poll-based source into a provider that
+// checks for new events on a fixed interval
+func NewPollingMatchDataProvider(poller MatchDataPoller, interval time.Duration) MatchDataProvider {
+	return &pollingMatchDataProvider{
+		poller:   poller,
+		interval: interval,
+	}
+}
+
+func (p *pollingMatchDataProvider) Name() string {
+	return p.poller.Name()
+}
+
+func (p *pollingMatchDataProvider) Start(ctx context.Context, matchID uuid.UUID, sink MatchEventSink) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	var since time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			events, err := p.poller.Poll(ctx, matchID, since)
+			if err != nil {
+				log.Printf("❌ Error polling %s for match %s: %v", p.poller.Name(), matchID, err)
+				continue
+			}
+
+			for i := range events {
+				if err := sink(&events[i]); err != nil {
+					return fmt.Errorf("failed to ingest event: %w", err)
+				}
+				if events[i].Timestamp.After(since) {
+					since = events[i].Timestamp
+				}
+			}
+		}
+	}
+}
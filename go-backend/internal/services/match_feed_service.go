@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"esports-fantasy-backend/config"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MatchFeedService connects match data providers to the scoring service,
+// running at most one feed per match
+type MatchFeedService struct {
+	cfg            *config.Config
+	scoringService ScoringService
+	mu             sync.Mutex
+	activeFeeds    map[uuid.UUID]*matchFeed
+}
+
+type matchFeed struct {
+	status MatchFeedStatus
+	cancel context.CancelFunc
+}
+
+type MatchFeedStatus struct {
+	MatchID        uuid.UUID `json:"match_id"`
+	Provider       string    `json:"provider"`
+	StartedAt      time.Time `json:"started_at"`
+	EventsIngested int       `json:"events_ingested"`
+}
+
+func NewMatchFeedService(cfg *config.Config, scoringService ScoringService) *MatchFeedService {
+	return &MatchFeedService{
+		cfg:            cfg,
+		scoringService: scoringService,
+		activeFeeds:    make(map[uuid.UUID]*matchFeed),
+	}
+}
+
+// StartFeed runs the provider in the background, recording every event it
+// delivers against the match
+func (s *MatchFeedService) StartFeed(matchID uuid.UUID, provider MatchDataProvider) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.activeFeeds[matchID]; exists {
+		return fmt.Errorf("a feed is already running for match %s", matchID)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	feed := &matchFeed{
+		status: MatchFeedStatus{
+			MatchID:   matchID,
+			Provider:  provider.Name(),
+			StartedAt: time.Now(),
+		},
+		cancel: cancel,
+	}
+	s.activeFeeds[matchID] = feed
+
+	sink := func(event *models.LiveMatchUpdateRequest) error {
+		if _, err := s.scoringService.RecordMatchEvent(matchID, event, EventSourceFeed); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		feed.status.EventsIngested++
+		s.mu.Unlock()
+		return nil
+	}
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			if s.activeFeeds[matchID] == feed {
+				delete(s.activeFeeds, matchID)
+			}
+			s.mu.Unlock()
+			cancel()
+		}()
+
+		log.Printf("📡 Match feed started: %s for match %s", provider.Name(), matchID)
+		if err := provider.Start(ctx, matchID, sink); err != nil {
+			log.Printf("❌ Match feed %s for match %s stopped: %v", provider.Name(), matchID, err)
+			return
+		}
+		log.Printf("🏁 Match feed finished: %s for match %s", provider.Name(), matchID)
+	}()
+
+	return nil
+}
+
+// StartReplay replays a recorded feed file from the configured feed
+// directory. A speed of 0 uses the configured default.
+func (s *MatchFeedService) StartReplay(matchID uuid.UUID, fileName string, speed float64) error {
+	if fileName == "" || filepath.Base(fileName) != fileName {
+		return fmt.Errorf("invalid feed file name: %q", fileName)
+	}
+
+	if speed == 0 {
+		speed = s.cfg.MatchFeedReplaySpeed
+	}
+
+	path := filepath.Join(s.cfg.MatchFeedDir, fileName)
+	return s.StartFeed(matchID, NewFileReplayProvider(path, speed))
+}
+
+func (s *MatchFeedService) StopFeed(matchID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed, exists := s.activeFeeds[matchID]
+	if !exists {
+		return fmt.Errorf("no active feed for match %s", matchID)
+	}
+
+	feed.cancel()
+	delete(s.activeFeeds, matchID)
+
+	log.Printf("⏹️ Match feed stopped for match %s", matchID)
+	return nil
+}
+
+func (s *MatchFeedService) GetActiveFeeds() []MatchFeedStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feeds := make([]MatchFeedStatus, 0, len(s.activeFeeds))
+	for _, feed := range s.activeFeeds {
+		feeds = append(feeds, feed.status)
+	}
+	return feeds
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetMatchPlacements records where each listed squad finished in the match
+// and rescores every player of those squads so their placement points flow
+// into fantasy teams. Squads not listed keep their current placement.
+func (s *scoringService) SetMatchPlacements(matchID uuid.UUID, results []models.MatchPlacementEntry) ([]models.MatchTeamPlacement, error) {
+	var match models.Match
+	if err := s.db.First(&match, "id = ?", matchID).Error; err != nil {
+		return nil, fmt.Errorf("match not found")
+	}
+
+	teamIDs := make([]uuid.UUID, 0, len(results))
+	placements := make([]int, 0, len(results))
+	seenTeams := make(map[uuid.UUID]bool)
+	seenPlacements := make(map[int]bool)
+	for _, result := range results {
+		if result.Placement < 1 {
+			return nil, fmt.Errorf("placement must be at least 1")
+		}
+		if seenTeams[result.ESportsTeamID] {
+			return nil, fmt.Errorf("team %s is listed more than once", result.ESportsTeamID)
+		}
+		if seenPlacements[result.Placement] {
+			return nil, fmt.Errorf("placement %d is listed more than once", result.Placement)
+		}
+		seenTeams[result.ESportsTeamID] = true
+		seenPlacements[result.Placement] = true
+		teamIDs = append(teamIDs, result.ESportsTeamID)
+		placements = append(placements, result.Placement)
+	}
+
+	var deltas []teamPointsDelta
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the match so concurrent updates see each other's placements
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&match, "id = ?", matchID).Error; err != nil {
+			return fmt.Errorf("failed to lock match: %w", err)
+		}
+
+		var teamCount int64
+		if err := tx.Model(&models.ESportsTeam{}).Where("id IN ?", teamIDs).Count(&teamCount).Error; err != nil {
+			return fmt.Errorf("failed to check teams: %w", err)
+		}
+		if int(teamCount) != len(teamIDs) {
+			return fmt.Errorf("one or more teams not found")
+		}
+
+		// Squads not listed keep their placement, so it cannot be given to another squad
+		var taken []models.MatchTeamPlacement
+		err := tx.Where("match_id = ? AND esports_team_id NOT IN ? AND placement IN ?", matchID, teamIDs, placements).
+			Find(&taken).Error
+		if err != nil {
+			return fmt.Errorf("failed to check placements: %w", err)
+		}
+		if len(taken) > 0 {
+			return fmt.Errorf("placement %d is already held by team %s", taken[0].Placement, taken[0].ESportsTeamID)
+		}
+
+		for _, result := range results {
+			placement := models.MatchTeamPlacement{
+				MatchID:       matchID,
+				ESportsTeamID: result.ESportsTeamID,
+			}
+			err := tx.Where("match_id = ? AND esports_team_id = ?", matchID, result.ESportsTeamID).
+				Assign(models.MatchTeamPlacement{Placement: result.Placement}).
+				FirstOrCreate(&placement).Error
+			if err != nil {
+				return fmt.Errorf("failed to save placement: %w", err)
+			}
+		}
+
+		var players []models.Player
+		if err := tx.Where("esports_team_id IN ?", teamIDs).Find(&players).Error; err != nil {
+			return fmt.Errorf("failed to get squad players: %w", err)
+		}
+
+		for _, player := range players {
+			// Seed the log from rows written before events existed
+			if _, err := s.loadPlayerEvents(tx, matchID, player.ID); err != nil {
+				return err
+			}
+
+			_, playerDeltas, err := s.rebuildPlayerStats(tx, matchID, player.ID)
+			if err != nil {
+				return err
+			}
+			deltas = append(deltas, playerDeltas...)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishTeamPointsDeltas(matchID, deltas)
+
+	log.Printf("✅ Recorded placements for %d squads in match %s", len(results), matchID)
+
+	return s.placementRepo.GetByMatchID(matchID)
+}
+
+func (s *scoringService) GetMatchPlacements(matchID uuid.UUID) ([]models.MatchTeamPlacement, error) {
+	return s.placementRepo.GetByMatchID(matchID)
+}
+
+// playerSquadPlacement returns where the player's squad finished in the
+// match, or 0 if no placement has been recorded
+func playerSquadPlacement(tx *gorm.DB, matchID, playerID uuid.UUID) (int, error) {
+	var placements []int
+	err := tx.Model(&models.MatchTeamPlacement{}).
+		Joins("JOIN players ON players.esports_team_id = match_team_placements.esports_team_id").
+		Where("match_team_placements.match_id = ? AND players.id = ?", matchID, playerID).
+		Pluck("match_team_placements.placement", &placements).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get squad placement: %w", err)
+	}
+
+	if len(placements) == 0 {
+		return 0, nil
+	}
+	return placements[0], nil
+}
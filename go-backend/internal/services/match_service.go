@@ -16,6 +16,8 @@ type MatchService interface {
 	UpdateMatchStatus(id uuid.UUID, status string) error
 	GetUpcomingMatches() ([]models.Match, error)
 	LockExpiredMatches() error
+	SetMatchTeams(matchID uuid.UUID, teamIDs []uuid.UUID) ([]models.MatchTeam, error)
+	GetMatchTeams(matchID uuid.UUID) ([]models.MatchTeam, error)
 }
 
 type matchService struct {
@@ -86,4 +88,33 @@ func (s *matchService) LockExpiredMatches() error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// SetMatchTeams records which squads play in a match. Fantasy teams for the
+// match can then only pick their players.
+func (s *matchService) SetMatchTeams(matchID uuid.UUID, teamIDs []uuid.UUID) ([]models.MatchTeam, error) {
+	if _, err := s.matchRepo.GetMatchByID(matchID); err != nil {
+		return nil, fmt.Errorf("match not found: %w", err)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(teamIDs))
+	for _, teamID := range teamIDs {
+		if seen[teamID] {
+			return nil, fmt.Errorf("team %s is listed more than once", teamID)
+		}
+		seen[teamID] = true
+	}
+
+	if err := s.matchRepo.SetMatchTeams(matchID, teamIDs); err != nil {
+		return nil, fmt.Errorf("failed to set match teams: %w", err)
+	}
+	return s.GetMatchTeams(matchID)
+}
+
+func (s *matchService) GetMatchTeams(matchID uuid.UUID) ([]models.MatchTeam, error) {
+	teams, err := s.matchRepo.GetMatchTeams(matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match teams: %w", err)
+	}
+	return teams, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"esports-fantasy-backend/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sources recorded on match stat events
+const (
+	EventSourceAdmin      = "admin"
+	EventSourceFeed       = "feed"
+	EventSourceCorrection = "correction"
+	EventSourceBaseline   = "baseline"
+)
+
+// Action types that can be posted as match stat events. "not_knocked" is
+// derived from survival time and is never posted directly.
+var statEventActionTypes = map[string]bool{
+	ActionKill:           true,
+	ActionKnockout:       true,
+	ActionRevive:         true,
+	ActionSurvivalMinute: true,
+	ActionMVP:            true,
+	ActionTeamKill:       true,
+}
+
+// foldMatchStatEvents derives a player's match stats from their event log.
+// Events are summed, so the result does not depend on arrival order.
+func foldMatchStatEvents(matchID, playerID uuid.UUID, events []models.MatchStatEvent) models.PlayerMatchStats {
+	stats := models.PlayerMatchStats{
+		MatchID:  matchID,
+		PlayerID: playerID,
+	}
+
+	for _, event := range events {
+		switch normalizeActionType(event.ActionType) {
+		case ActionKill:
+			stats.Kills += event.Value
+		case ActionKnockout:
+			stats.Knockouts += event.Value
+		case ActionRevive:
+			stats.Revives += event.Value
+		case ActionSurvivalMinute:
+			stats.SurvivalTimeMinutes += event.Value
+		case ActionTeamKill:
+			stats.TeamKillPenalty += event.Value
+		}
+	}
+	stats.IsMVP = mvpCount(events) > 0
+
+	return stats
+}
+
+// mvpCount sums a player's mvp events. Anything above zero folds to MVP, so
+// the sum rather than IsMVP says how much a correction has to take back.
+func mvpCount(events []models.MatchStatEvent) int {
+	count := 0
+	for _, event := range events {
+		if normalizeActionType(event.ActionType) == ActionMVP {
+			count += event.Value
+		}
+	}
+	return count
+}
+
+// statDeltaEvents returns the events that move a player's stats from current
+// to target, one per changed action type. currentMVP is the summed mvp events
+// behind current, so the mvp event brings that sum to exactly 0 or 1.
+func statDeltaEvents(current, target *models.PlayerMatchStats, currentMVP int, source string) []models.MatchStatEvent {
+	boolValue := func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	deltas := []struct {
+		actionType string
+		value      int
+	}{
+		{ActionKill, target.Kills - current.Kills},
+		{ActionKnockout, target.Knockouts - current.Knockouts},
+		{ActionRevive, target.Revives - current.Revives},
+		{ActionSurvivalMinute, target.SurvivalTimeMinutes - current.SurvivalTimeMinutes},
+		{ActionMVP, boolValue(target.IsMVP) - currentMVP},
+		{ActionTeamKill, target.TeamKillPenalty - current.TeamKillPenalty},
+	}
+
+	now := time.Now()
+	events := []models.MatchStatEvent{}
+	for _, delta := range deltas {
+		if delta.value == 0 {
+			continue
+		}
+		events = append(events, models.MatchStatEvent{
+			MatchID:    target.MatchID,
+			PlayerID:   target.PlayerID,
+			ActionType: delta.actionType,
+			Value:      delta.value,
+			OccurredAt: now,
+			Source:     source,
+		})
+	}
+	return events
+}
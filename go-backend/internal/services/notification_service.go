@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"esports-fantasy-backend/internal/models"
+	"esports-fantasy-backend/internal/repository"
+	"fmt"
+	"log"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+type NotificationService interface {
+	Notify(userID uuid.UUID, notificationType, title, message string, data interface{}) error
+	GetUserNotifications(userID uuid.UUID, limit int) ([]models.Notification, int64, error)
+	MarkAsRead(id, userID uuid.UUID) error
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	rdb              *redis.Client
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository, rdb *redis.Client) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		rdb:              rdb,
+	}
+}
+
+// Notify stores a notification for the user and publishes it on the
+// user_notifications channel for connected clients
+func (s *notificationService) Notify(userID uuid.UUID, notificationType, title, message string, data interface{}) error {
+	notification := &models.Notification{
+		UserID:  userID,
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+	}
+
+	if data != nil {
+		dataJSON, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to serialize notification data: %w", err)
+		}
+		notification.Data = string(dataJSON)
+	}
+
+	if err := s.notificationRepo.Create(notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	eventData, _ := json.Marshal(notification)
+	if err := s.rdb.Publish(context.Background(), "user_notifications", eventData).Err(); err != nil {
+		log.Printf("Error publishing notification: %v", err)
+	}
+
+	return nil
+}
+
+func (s *notificationService) GetUserNotifications(userID uuid.UUID, limit int) ([]models.Notification, int64, error) {
+	notifications, err := s.notificationRepo.GetByUserID(userID, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, unread, nil
+}
+
+func (s *notificationService) MarkAsRead(id, userID uuid.UUID) error {
+	return s.notificationRepo.MarkAsRead(id, userID)
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"esports-fantasy-backend/config"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Deposit statuses, the same for every gateway
+const (
+	DepositStatusPending   = "pending"
+	DepositStatusCompleted = "completed"
+	DepositStatusFailed    = "failed"
+	DepositStatusRefunded  = "refunded"
+)
+
+// Payment gateway names
+const (
+	GatewayPhonePe  = "phonepe"
+	GatewayRazorpay = "razorpay"
+	GatewayFake     = "fake"
+)
+
+var (
+	ErrUnknownGateway  = errors.New("unknown payment gateway")
+	ErrInvalidCallback = errors.New("invalid payment callback")
+)
+
+// PaymentInitiation asks a gateway to collect a deposit
+type PaymentInitiation struct {
+	TransactionID uuid.UUID
+	UserID        uuid.UUID
+	Amount        float64
+	MobileNumber  string
+	Purpose       string
+}
+
+// PaymentSession tells the client how to complete a payment
+type PaymentSession struct {
+	TransactionID uuid.UUID              `json:"transaction_id"`
+	Gateway       string                 `json:"gateway"`
+	OrderID       string                 `json:"order_id,omitempty"`     // Gateway's order reference, if it has one
+	RedirectURL   string                 `json:"redirect_url,omitempty"` // Hosted payment page, if any
+	PublicKey     string                 `json:"public_key,omitempty"`   // Key the client SDK needs, if any
+	Data          map[string]interface{} `json:"data,omitempty"`         // Gateway-specific response
+}
+
+// PaymentStatus is a gateway's view of a payment, with the state mapped to
+// a deposit status
+type PaymentStatus struct {
+	TransactionID uuid.UUID // Zero when the gateway only knows its order ID
+	OrderID       string
+	PaymentID     string
+	Amount        float64 // Zero when the gateway did not report it
+	Status        string
+	GatewayState  string
+}
+
+// PaymentCallback is a gateway notification as received over HTTP. Gateways
+// read the fields they sign.
+type PaymentCallback struct {
+	Payload   string
+	Signature string
+	OrderID   string
+	PaymentID string
+}
+
+// RefundRequest returns a completed deposit to its payment method
+type RefundRequest struct {
+	RefundID      uuid.UUID
+	TransactionID uuid.UUID
+	UserID        uuid.UUID
+	OrderID       string
+	PaymentID     string
+	Amount        float64
+}
+
+// RefundResult is the gateway's record of a refund
+type RefundResult struct {
+	Reference string
+}
+
+// PaymentGateway collects deposits. Implementations only talk to the
+// gateway; DepositService owns the transaction and the wallet.
+type PaymentGateway interface {
+	Name() string
+	Initiate(ctx context.Context, req *PaymentInitiation) (*PaymentSession, error)
+	Status(ctx context.Context, transaction *models.Transaction) (*PaymentStatus, error)
+	VerifyCallback(callback *PaymentCallback) (*PaymentStatus, error)
+	Refund(ctx context.Context, req *RefundRequest) (*RefundResult, error)
+}
+
+// NewPaymentGateways returns the gateways deposits can use, by name. In dummy
+// mode every gateway is an in-process fake that completes payments on its
+// own after a short delay.
+func NewPaymentGateways(cfg *config.Config) map[string]PaymentGateway {
+	if cfg.Dummy {
+		return map[string]PaymentGateway{
+			GatewayPhonePe:  NewFakePaymentGateway(GatewayPhonePe, cfg.PhonePeRedirectURL, 10*time.Second),
+			GatewayRazorpay: NewFakePaymentGateway(GatewayRazorpay, cfg.PhonePeRedirectURL, 10*time.Second),
+			GatewayFake:     NewFakePaymentGateway(GatewayFake, cfg.PhonePeRedirectURL, 10*time.Second),
+		}
+	}
+
+	return map[string]PaymentGateway{
+		GatewayPhonePe:  NewPhonePeGateway(cfg),
+		GatewayRazorpay: NewRazorpayGateway(cfg),
+	}
+}
+
+// FakePaymentGateway collects payments in process without moving money, for
+// development and tests. Payments complete once autoCompleteAfter has passed,
+// or when Complete or Fail is called.
+type FakePaymentGateway struct {
+	name              string
+	redirectURL       string
+	autoCompleteAfter time.Duration
+
+	mu       sync.Mutex
+	payments map[uuid.UUID]string
+}
+
+func NewFakePaymentGateway(name, redirectURL string, autoCompleteAfter time.Duration) *FakePaymentGateway {
+	return &FakePaymentGateway{
+		name:              name,
+		redirectURL:       redirectURL,
+		autoCompleteAfter: autoCompleteAfter,
+		payments:          make(map[uuid.UUID]string),
+	}
+}
+
+func (g *FakePaymentGateway) Name() string {
+	return g.name
+}
+
+func (g *FakePaymentGateway) Initiate(ctx context.Context, req *PaymentInitiation) (*PaymentSession, error) {
+	g.mu.Lock()
+	g.payments[req.TransactionID] = DepositStatusPending
+	g.mu.Unlock()
+
+	return &PaymentSession{
+		TransactionID: req.TransactionID,
+		Gateway:       g.name,
+		OrderID:       fakeOrderID(g.name, req.TransactionID),
+		RedirectURL:   fmt.Sprintf("%s/payment/test?txnId=%s&amount=%.2f", g.redirectURL, req.TransactionID, req.Amount),
+		PublicKey:     "fake_public_key",
+	}, nil
+}
+
+func (g *FakePaymentGateway) Status(ctx context.Context, transaction *models.Transaction) (*PaymentStatus, error) {
+	g.mu.Lock()
+	status, ok := g.payments[transaction.ID]
+	g.mu.Unlock()
+
+	if (!ok || status == DepositStatusPending) && g.autoCompleteAfter > 0 &&
+		time.Since(transaction.CreatedAt) > g.autoCompleteAfter {
+		status = DepositStatusCompleted
+	}
+	if status == "" {
+		status = DepositStatusPending
+	}
+
+	return g.paymentStatus(transaction.ID, transaction.Amount, status), nil
+}
+
+// VerifyCallback accepts any callback naming one of the fake's orders and
+// reports it as paid
+func (g *FakePaymentGateway) VerifyCallback(callback *PaymentCallback) (*PaymentStatus, error) {
+	prefix := g.name + "_order_"
+	if !strings.HasPrefix(callback.OrderID, prefix) {
+		return nil, fmt.Errorf("%w: unknown order %q", ErrInvalidCallback, callback.OrderID)
+	}
+	transactionID, err := uuid.Parse(strings.TrimPrefix(callback.OrderID, prefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown order %q", ErrInvalidCallback, callback.OrderID)
+	}
+
+	g.Complete(transactionID)
+	status := g.paymentStatus(transactionID, 0, DepositStatusCompleted)
+	if callback.PaymentID != "" {
+		status.PaymentID = callback.PaymentID
+	}
+	return status, nil
+}
+
+func (g *FakePaymentGateway) Refund(ctx context.Context, req *RefundRequest) (*RefundResult, error) {
+	g.mu.Lock()
+	g.payments[req.TransactionID] = DepositStatusRefunded
+	g.mu.Unlock()
+
+	return &RefundResult{Reference: fmt.Sprintf("%s_refund_%s", g.name, req.RefundID)}, nil
+}
+
+// Complete marks a payment as paid
+func (g *FakePaymentGateway) Complete(transactionID uuid.UUID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.payments[transactionID] = DepositStatusCompleted
+}
+
+// Fail marks a payment as declined
+func (g *FakePaymentGateway) Fail(transactionID uuid.UUID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.payments[transactionID] = DepositStatusFailed
+}
+
+func (g *FakePaymentGateway) paymentStatus(transactionID uuid.UUID, amount float64, status string) *PaymentStatus {
+	paymentStatus := &PaymentStatus{
+		TransactionID: transactionID,
+		OrderID:       fakeOrderID(g.name, transactionID),
+		Amount:        amount,
+		Status:        status,
+		GatewayState:  status,
+	}
+	if status != DepositStatusPending {
+		paymentStatus.PaymentID = fmt.Sprintf("%s_pay_%s", g.name, transactionID)
+	}
+	return paymentStatus
+}
+
+func fakeOrderID(gateway string, transactionID uuid.UUID) string {
+	return fmt.Sprintf("%s_order_%s", gateway, transactionID)
+}
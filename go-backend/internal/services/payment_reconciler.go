@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"esports-fantasy-backend/config"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Reasons a deposit shows up in a reconciliation report
+const (
+	DepositIssueStatusMismatch = "status_mismatch" // Our status differs from the gateway's
+	DepositIssueAmountMismatch = "amount_mismatch" // Gateway collected a different amount
+	DepositIssueLedgerMismatch = "ledger_mismatch" // Wallet credit does not match our status
+	DepositIssueGatewayError   = "gateway_error"   // Gateway status could not be fetched
+)
+
+// reconcileBatchSize caps how many pending deposits one sweep checks
+const reconcileBatchSize = 200
+
+// DepositSweepResult - What one pass over stuck pending deposits did
+type DepositSweepResult struct {
+	Checked   int `json:"checked"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+	Expired   int `json:"expired"`
+	Errors    int `json:"errors"`
+}
+
+// DepositMismatch - A deposit where our records and the gateway disagree
+type DepositMismatch struct {
+	TransactionID  string  `json:"transaction_id"`
+	Gateway        string  `json:"gateway"`
+	Amount         float64 `json:"amount"`
+	Status         string  `json:"status"`
+	GatewayStatus  string  `json:"gateway_status,omitempty"`
+	GatewayAmount  float64 `json:"gateway_amount,omitempty"`
+	LedgerCredited bool    `json:"ledger_credited"`
+	Issue          string  `json:"issue"`
+	Detail         string  `json:"detail,omitempty"`
+}
+
+// PaymentReconciler settles deposits whose callback never arrived and
+// reports daily on where our ledger and the gateways disagree
+type PaymentReconciler struct {
+	cfg            *config.Config
+	db             *gorm.DB
+	depositService DepositService
+	gateways       map[string]PaymentGateway
+	cron           *cron.Cron
+}
+
+func NewPaymentReconciler(cfg *config.Config, db *gorm.DB, depositService DepositService, gateways map[string]PaymentGateway) *PaymentReconciler {
+	return &PaymentReconciler{
+		cfg:            cfg,
+		db:             db,
+		depositService: depositService,
+		gateways:       gateways,
+		cron:           cron.New(),
+	}
+}
+
+func (r *PaymentReconciler) StartScheduler() error {
+	if !r.cfg.PaymentReconciliationEnabled {
+		log.Println("💳 Payment reconciliation is disabled")
+		return nil
+	}
+
+	// Check stuck pending deposits (runs every 5 minutes)
+	if _, err := r.cron.AddFunc("@every 5m", r.autoReconcilePending); err != nil {
+		return fmt.Errorf("failed to schedule deposit sweep: %w", err)
+	}
+
+	// Report on yesterday's deposits (runs daily at 00:30)
+	if _, err := r.cron.AddFunc("30 0 * * *", r.autoDailyReport); err != nil {
+		return fmt.Errorf("failed to schedule reconciliation report: %w", err)
+	}
+
+	r.cron.Start()
+	log.Println("💳 Payment reconciliation scheduler started")
+	return nil
+}
+
+func (r *PaymentReconciler) StopScheduler() {
+	if r.cron != nil {
+		r.cron.Stop()
+	}
+}
+
+func (r *PaymentReconciler) autoReconcilePending() {
+	result, err := r.ReconcilePending()
+	if err != nil {
+		log.Printf("❌ Deposit sweep failed: %v", err)
+		return
+	}
+	if result.Checked > 0 {
+		log.Printf("💳 Deposit sweep: %d checked, %d completed, %d failed, %d expired, %d errors",
+			result.Checked, result.Completed, result.Failed, result.Expired, result.Errors)
+	}
+}
+
+func (r *PaymentReconciler) autoDailyReport() {
+	report, err := r.GenerateDailyReport(time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		log.Printf("❌ Payment reconciliation report failed: %v", err)
+		return
+	}
+	if report.MismatchCount > 0 {
+		log.Printf("⚠️ Payment reconciliation for %s found %d mismatches", report.ReportDate, report.MismatchCount)
+	}
+}
+
+// ReconcilePending asks the gateways about deposits that have been pending
+// longer than DepositPendingMinutes, and fails those still pending, or still
+// unchecked, after DepositExpiryHours
+func (r *PaymentReconciler) ReconcilePending() (*DepositSweepResult, error) {
+	now := time.Now()
+	pendingBefore := now.Add(-time.Duration(r.cfg.DepositPendingMinutes) * time.Minute)
+	expireBefore := now.Add(-time.Duration(r.cfg.DepositExpiryHours) * time.Hour)
+
+	var deposits []models.Transaction
+	err := r.db.Where("type = ? AND status = ? AND created_at < ?", TransactionTypeDeposit, DepositStatusPending, pendingBefore).
+		Order("created_at ASC").
+		Limit(reconcileBatchSize).
+		Find(&deposits).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending deposits: %w", err)
+	}
+
+	result := &DepositSweepResult{}
+	for _, deposit := range deposits {
+		result.Checked++
+
+		// A deposit whose status cannot be checked still expires, so rows
+		// that keep erroring age out instead of filling every batch
+		status := DepositStatusPending
+		updated, err := r.depositService.CheckStatus(deposit.ID)
+		if err != nil {
+			log.Printf("Error checking deposit %s: %v", deposit.ID, err)
+			if !deposit.CreatedAt.Before(expireBefore) {
+				result.Errors++
+				continue
+			}
+		} else {
+			status = updated.Status
+		}
+
+		switch status {
+		case DepositStatusCompleted:
+			result.Completed++
+		case DepositStatusFailed:
+			result.Failed++
+		case DepositStatusPending:
+			if deposit.CreatedAt.Before(expireBefore) {
+				if _, err := r.depositService.ExpireDeposit(deposit.ID); err != nil {
+					log.Printf("Error expiring deposit %s: %v", deposit.ID, err)
+					result.Errors++
+					continue
+				}
+				result.Expired++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// GenerateDailyReport checks every deposit made on the given day against its
+// gateway and stores the result, replacing an earlier report for that day
+func (r *PaymentReconciler) GenerateDailyReport(day time.Time) (*models.PaymentReconciliationReport, error) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+
+	var deposits []models.Transaction
+	err := r.db.Where("type = ? AND created_at >= ? AND created_at < ?", TransactionTypeDeposit, start, end).
+		Order("created_at ASC").
+		Find(&deposits).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deposits: %w", err)
+	}
+
+	report := &models.PaymentReconciliationReport{
+		ReportDate:  start.Format("2006-01-02"),
+		GeneratedAt: time.Now(),
+	}
+	mismatches := []DepositMismatch{}
+	for i := range deposits {
+		check, err := r.checkDeposit(&deposits[i])
+		if err != nil {
+			return nil, err
+		}
+
+		report.DepositsChecked++
+		report.GatewayTotal += check.GatewayPaid
+		if check.LedgerCredited {
+			report.LedgerTotal += deposits[i].Amount
+		}
+		if check.Issue == "" {
+			report.Matched++
+		} else {
+			mismatches = append(mismatches, check.DepositMismatch)
+		}
+	}
+
+	report.LedgerTotal = roundCurrency(report.LedgerTotal)
+	report.GatewayTotal = roundCurrency(report.GatewayTotal)
+	report.MismatchCount = len(mismatches)
+	mismatchesJSON, err := json.Marshal(mismatches)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode mismatches: %w", err)
+	}
+	report.Mismatches = string(mismatchesJSON)
+
+	err = r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "report_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"deposits_checked", "matched", "mismatch_count", "ledger_total", "gateway_total", "mismatches", "generated_at"}),
+	}).Create(report).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to save reconciliation report: %w", err)
+	}
+
+	log.Printf("📒 Payment reconciliation for %s: %d deposits, %d mismatches", report.ReportDate, report.DepositsChecked, report.MismatchCount)
+
+	return r.GetDailyReport(start)
+}
+
+func (r *PaymentReconciler) GetDailyReport(day time.Time) (*models.PaymentReconciliationReport, error) {
+	var report models.PaymentReconciliationReport
+	if err := r.db.First(&report, "report_date = ?", day.Format("2006-01-02")).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+type depositCheck struct {
+	DepositMismatch
+	GatewayPaid float64
+}
+
+// checkDeposit compares one deposit with its wallet credit and with what its
+// gateway says was paid. Issue is empty when they all agree; a deposit we
+// failed that the gateway still shows pending moved no money and agrees.
+func (r *PaymentReconciler) checkDeposit(deposit *models.Transaction) (*depositCheck, error) {
+	var credits int64
+	err := r.db.Model(&models.JournalEntry{}).
+		Where("kind = ? AND transaction_id = ?", JournalKindDeposit, deposit.ID).
+		Count(&credits).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check ledger: %w", err)
+	}
+
+	check := &depositCheck{DepositMismatch: DepositMismatch{
+		TransactionID:  deposit.ID.String(),
+		Gateway:        deposit.Gateway,
+		Amount:         deposit.Amount,
+		Status:         deposit.Status,
+		LedgerCredited: credits > 0,
+	}}
+
+	paid := deposit.Status == DepositStatusCompleted || deposit.Status == DepositStatusRefunded
+	if paid != check.LedgerCredited {
+		check.Issue = DepositIssueLedgerMismatch
+	}
+
+	gateway, ok := r.gateways[deposit.Gateway]
+	if !ok {
+		check.Issue = DepositIssueGatewayError
+		check.Detail = fmt.Sprintf("%s: %s", ErrUnknownGateway, deposit.Gateway)
+		return check, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gatewayTimeout)
+	defer cancel()
+
+	status, err := gateway.Status(ctx, deposit)
+	if err != nil {
+		if check.Issue == "" {
+			check.Issue = DepositIssueGatewayError
+		}
+		check.Detail = err.Error()
+		return check, nil
+	}
+	check.GatewayStatus = status.Status
+	check.GatewayAmount = status.Amount
+
+	if status.Status == DepositStatusCompleted || status.Status == DepositStatusRefunded {
+		check.GatewayPaid = status.Amount
+		if check.GatewayPaid == 0 {
+			check.GatewayPaid = deposit.Amount
+		}
+	}
+
+	switch {
+	case check.Issue != "":
+	case paid != (check.GatewayPaid > 0):
+		check.Issue = DepositIssueStatusMismatch
+	case status.Amount > 0 && roundCurrency(status.Amount) != roundCurrency(deposit.Amount):
+		check.Issue = DepositIssueAmountMismatch
+	}
+	return check, nil
+}
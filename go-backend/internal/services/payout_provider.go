@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"esports-fantasy-backend/config"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// PayoutRequest is money to send to a user's bank account or UPI ID
+type PayoutRequest struct {
+	WithdrawalID uuid.UUID
+	UserID       uuid.UUID
+	Amount       float64
+	Method       string
+	Destination  string
+}
+
+// PayoutResult is the provider's record of a completed payout
+type PayoutResult struct {
+	Reference string
+}
+
+// PayoutProvider sends withdrawal payouts. Payout must be idempotent on
+// WithdrawalID so that a retried request never pays twice, and returns an
+// error only when no money has moved.
+type PayoutProvider interface {
+	Name() string
+	Payout(ctx context.Context, req *PayoutRequest) (*PayoutResult, error)
+}
+
+// NewPayoutProvider returns the payout provider named in config, or nil when
+// none is configured and withdrawals are disabled
+func NewPayoutProvider(cfg *config.Config) (PayoutProvider, error) {
+	switch cfg.PayoutProvider {
+	case "":
+		return nil, nil
+	case "fake":
+		// The fake provider marks payouts paid without sending any money
+		if !cfg.Dummy {
+			return nil, fmt.Errorf("the fake payout provider is only allowed in dummy mode (DUMMY=true)")
+		}
+		return NewFakePayoutProvider(), nil
+	}
+	return nil, fmt.Errorf("unknown payout provider: %s", cfg.PayoutProvider)
+}
+
+// FakePayoutProvider pays out locally without moving money, for development
+// and tests. Destinations containing "fail" are rejected so failures can be
+// exercised end to end.
+type FakePayoutProvider struct {
+	mu      sync.Mutex
+	payouts map[uuid.UUID]*PayoutResult
+}
+
+func NewFakePayoutProvider() *FakePayoutProvider {
+	return &FakePayoutProvider{
+		payouts: make(map[uuid.UUID]*PayoutResult),
+	}
+}
+
+func (p *FakePayoutProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakePayoutProvider) Payout(ctx context.Context, req *PayoutRequest) (*PayoutResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, ok := p.payouts[req.WithdrawalID]; ok {
+		return result, nil
+	}
+	if strings.Contains(strings.ToLower(req.Destination), "fail") {
+		return nil, fmt.Errorf("payout to %s was declined", req.Destination)
+	}
+
+	result := &PayoutResult{Reference: fmt.Sprintf("FAKE_PAYOUT_%s", req.WithdrawalID)}
+	p.payouts[req.WithdrawalID] = result
+	return result, nil
+}
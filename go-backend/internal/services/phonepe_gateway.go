@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"esports-fantasy-backend/config"
+	"esports-fantasy-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// PhonePeGateway collects deposits through the PhonePe PG pay page. The
+// merchantTransactionId sent to PhonePe is our transaction ID.
+type PhonePeGateway struct {
+	cfg    *config.Config
+	client *http.Client
+}
+
+type PhonePePaymentRequest struct {
+	MerchantID            string `json:"merchantId"`
+	MerchantTransactionID string `json:"merchantTransactionId"`
+	Amount                int64  `json:"amount"` // Amount in paise
+	MerchantUserID        string `json:"merchantUserId"`
+	RedirectURL           string `json:"redirectUrl"`
+	RedirectMode          string `json:"redirectMode"`
+	CallbackURL           string `json:"callbackUrl"`
+	MobileNumber          string `json:"mobileNumber,omitempty"`
+	PaymentInstrument     struct {
+		Type string `json:"type"`
+	} `json:"paymentInstrument"`
+}
+
+type PhonePeRefundRequest struct {
+	MerchantID            string `json:"merchantId"`
+	MerchantUserID        string `json:"merchantUserId"`
+	OriginalTransactionID string `json:"originalTransactionId"`
+	MerchantTransactionID string `json:"merchantTransactionId"`
+	Amount                int64  `json:"amount"` // Amount in paise
+	CallbackURL           string `json:"callbackUrl"`
+}
+
+type PhonePeRequest struct {
+	Request string `json:"request"`
+}
+
+type PhonePeResponse struct {
+	Success bool            `json:"success"`
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type PhonePePaymentData struct {
+	MerchantID            string `json:"merchantId"`
+	MerchantTransactionID string `json:"merchantTransactionId"`
+	TransactionID         string `json:"transactionId"`
+	Amount                int64  `json:"amount"`
+	State                 string `json:"state"`
+	ResponseCode          string `json:"responseCode"`
+	PaymentInstrument     struct {
+		Type string `json:"type"`
+	} `json:"paymentInstrument"`
+}
+
+type phonePePayPageData struct {
+	InstrumentResponse struct {
+		RedirectInfo struct {
+			URL string `json:"url"`
+		} `json:"redirectInfo"`
+	} `json:"instrumentResponse"`
+}
+
+func NewPhonePeGateway(cfg *config.Config) *PhonePeGateway {
+	return &PhonePeGateway{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (g *PhonePeGateway) Name() string {
+	return GatewayPhonePe
+}
+
+func (g *PhonePeGateway) Initiate(ctx context.Context, req *PaymentInitiation) (*PaymentSession, error) {
+	paymentReq := PhonePePaymentRequest{
+		MerchantID:            g.cfg.PhonePeMerchantID,
+		MerchantTransactionID: req.TransactionID.String(),
+		Amount:                toPaise(req.Amount),
+		MerchantUserID:        req.UserID.String(),
+		RedirectURL:           g.cfg.PhonePeRedirectURL,
+		RedirectMode:          "REDIRECT",
+		CallbackURL:           g.cfg.PhonePeCallbackURL,
+		MobileNumber:          req.MobileNumber,
+	}
+	paymentReq.PaymentInstrument.Type = "PAY_PAGE"
+
+	resp, err := g.post(ctx, "/pg/v1/pay", paymentReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var data phonePePayPageData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode pay page response: %w", err)
+	}
+
+	return &PaymentSession{
+		TransactionID: req.TransactionID,
+		Gateway:       GatewayPhonePe,
+		RedirectURL:   data.InstrumentResponse.RedirectInfo.URL,
+	}, nil
+}
+
+func (g *PhonePeGateway) Status(ctx context.Context, transaction *models.Transaction) (*PaymentStatus, error) {
+	endpoint := fmt.Sprintf("/pg/v1/status/%s/%s", g.cfg.PhonePeMerchantID, transaction.ID)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, g.cfg.PhonePeBaseURL+endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("X-VERIFY", g.createChecksum("", endpoint))
+
+	resp, err := g.do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var data PhonePePaymentData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode payment status: %w", err)
+	}
+	if data.State == "" {
+		// Errors such as PAYMENT_ERROR come back without payment data
+		data.State = resp.Code
+	}
+
+	return phonePePaymentStatus(&data), nil
+}
+
+// VerifyCallback checks the X-VERIFY checksum of a server-to-server callback
+// and decodes its base64 payload
+func (g *PhonePeGateway) VerifyCallback(callback *PaymentCallback) (*PaymentStatus, error) {
+	if !hmac.Equal([]byte(g.createChecksum(callback.Payload, "/pg/v1/status")), []byte(callback.Signature)) {
+		return nil, fmt.Errorf("%w: invalid checksum", ErrInvalidCallback)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(callback.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode payload: %v", ErrInvalidCallback, err)
+	}
+
+	// Callbacks wrap the payment in a response envelope; older ones did not
+	var envelope struct {
+		Code string             `json:"code"`
+		Data PhonePePaymentData `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal payload: %v", ErrInvalidCallback, err)
+	}
+	data := envelope.Data
+	if data.MerchantTransactionID == "" {
+		if err := json.Unmarshal(payload, &data); err != nil {
+			return nil, fmt.Errorf("%w: failed to unmarshal payload: %v", ErrInvalidCallback, err)
+		}
+	}
+	if data.State == "" {
+		data.State = envelope.Code
+	}
+
+	return phonePePaymentStatus(&data), nil
+}
+
+func (g *PhonePeGateway) Refund(ctx context.Context, req *RefundRequest) (*RefundResult, error) {
+	refundReq := PhonePeRefundRequest{
+		MerchantID:            g.cfg.PhonePeMerchantID,
+		MerchantUserID:        req.UserID.String(),
+		OriginalTransactionID: req.TransactionID.String(),
+		MerchantTransactionID: req.RefundID.String(),
+		Amount:                toPaise(req.Amount),
+		CallbackURL:           g.cfg.PhonePeCallbackURL,
+	}
+
+	resp, err := g.post(ctx, "/pg/v1/refund", refundReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var data PhonePePaymentData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode refund response: %w", err)
+	}
+	if data.State == "FAILED" {
+		return nil, fmt.Errorf("phonepe refund failed: %s", data.ResponseCode)
+	}
+
+	return &RefundResult{Reference: data.TransactionID}, nil
+}
+
+// phonePePaymentStatus maps a PhonePe payment state to a deposit status
+func phonePePaymentStatus(data *PhonePePaymentData) *PaymentStatus {
+	status := &PaymentStatus{
+		OrderID:      data.MerchantTransactionID,
+		PaymentID:    data.TransactionID,
+		Amount:       float64(data.Amount) / 100,
+		GatewayState: data.State,
+	}
+	if transactionID, err := uuid.Parse(data.MerchantTransactionID); err == nil {
+		status.TransactionID = transactionID
+	}
+
+	switch data.State {
+	case "COMPLETED", "PAYMENT_SUCCESS":
+		status.Status = DepositStatusCompleted
+	case "FAILED", "PAYMENT_ERROR", "PAYMENT_DECLINED":
+		status.Status = DepositStatusFailed
+	default:
+		status.Status = DepositStatusPending
+	}
+	return status
+}
+
+func (g *PhonePeGateway) createChecksum(payload, endpoint string) string {
+	data := payload + endpoint + g.cfg.PhonePeSaltKey
+	hash := hmac.New(sha256.New, []byte(g.cfg.PhonePeSaltKey))
+	hash.Write([]byte(data))
+	return hex.EncodeToString(hash.Sum(nil)) + "###" + fmt.Sprintf("%d", g.cfg.PhonePeSaltIndex)
+}
+
+// post sends a base64-encoded, checksummed request to a PhonePe endpoint
+func (g *PhonePeGateway) post(ctx context.Context, endpoint string, body interface{}) (*PhonePeResponse, error) {
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	payload := base64.StdEncoding.EncodeToString(bodyJSON)
+
+	reqJSON, err := json.Marshal(PhonePeRequest{Request: payload})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, g.cfg.PhonePeBaseURL+endpoint, strings.NewReader(string(reqJSON)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-VERIFY", g.createChecksum(payload, endpoint))
+
+	return g.do(httpReq)
+}
+
+func (g *PhonePeGateway) do(httpReq *http.Request) (*PhonePeResponse, error) {
+	httpReq.Header.Set("X-MERCHANT-ID", g.cfg.PhonePeMerchantID)
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var phonePeResp PhonePeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&phonePeResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !phonePeResp.Success && len(phonePeResp.Data) == 0 {
+		return nil, fmt.Errorf("phonepe error %s: %s", phonePeResp.Code, phonePeResp.Message)
+	}
+
+	return &phonePeResp, nil
+}
+
+// toPaise converts rupees to the integer paise gateways expect
+func toPaise(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
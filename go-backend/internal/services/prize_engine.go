@@ -0,0 +1,300 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Prize pool types
+const (
+	PrizePoolEntryBased = "entry_based" // Entry fees minus rake
+	PrizePoolGuaranteed = "guaranteed"  // Fixed amount regardless of fill
+)
+
+// Platform share of entry fees for contests without a prize structure
+const DefaultRakePercent = 10.0
+
+// ErrInvalidPrizeStructure is wrapped by every prize structure validation error
+var ErrInvalidPrizeStructure = errors.New("invalid prize structure")
+
+// PrizeEngine computes prize pools and per-rank payouts from a contest's
+// prize structure
+type PrizeEngine struct {
+	structure models.PrizeStructure
+}
+
+// DefaultPrizeStructure pays 50/30/20% of the entry-based pool to the top 3
+func DefaultPrizeStructure() models.PrizeStructure {
+	return models.PrizeStructure{
+		PoolType:    PrizePoolEntryBased,
+		RakePercent: DefaultRakePercent,
+		Ranks: []models.PrizeRankRange{
+			{From: 1, To: 1, Percent: 50},
+			{From: 2, To: 2, Percent: 30},
+			{From: 3, To: 3, Percent: 20},
+		},
+	}
+}
+
+// NewPrizeEngine parses a prize structure from its JSON form. An empty value
+// uses the default structure, and the legacy {"1": 25000, "2": 15000} rank
+// map is read as guaranteed fixed prizes.
+func NewPrizeEngine(raw string) (*PrizeEngine, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "{}" || raw == "null" {
+		return newPrizeEngine(DefaultPrizeStructure())
+	}
+
+	var structure models.PrizeStructure
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&structure); err == nil {
+		return newPrizeEngine(structure)
+	}
+
+	legacy, err := parseLegacyPrizePool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrizeStructure, err)
+	}
+	return newPrizeEngine(legacy)
+}
+
+func newPrizeEngine(structure models.PrizeStructure) (*PrizeEngine, error) {
+	if structure.PoolType == "" {
+		structure.PoolType = PrizePoolEntryBased
+	}
+
+	sort.Slice(structure.Ranks, func(i, j int) bool {
+		return structure.Ranks[i].From < structure.Ranks[j].From
+	})
+
+	engine := &PrizeEngine{structure: structure}
+	if err := engine.validate(); err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
+// parseLegacyPrizePool reads a {"rank": amount} map
+func parseLegacyPrizePool(raw string) (models.PrizeStructure, error) {
+	var amounts map[string]float64
+	if err := json.Unmarshal([]byte(raw), &amounts); err != nil {
+		return models.PrizeStructure{}, fmt.Errorf("unrecognised format: %v", err)
+	}
+
+	structure := models.PrizeStructure{PoolType: PrizePoolGuaranteed}
+	for key, amount := range amounts {
+		rank, err := strconv.Atoi(strings.TrimSpace(key))
+		if err != nil {
+			return models.PrizeStructure{}, fmt.Errorf("invalid rank %q", key)
+		}
+		structure.Ranks = append(structure.Ranks, models.PrizeRankRange{From: rank, To: rank, Amount: amount})
+		structure.GuaranteedAmount += amount
+	}
+	return structure, nil
+}
+
+func (e *PrizeEngine) validate() error {
+	s := e.structure
+
+	switch s.PoolType {
+	case PrizePoolEntryBased:
+	case PrizePoolGuaranteed:
+		if s.GuaranteedAmount <= 0 {
+			return fmt.Errorf("%w: guaranteed pools need a guaranteed_amount", ErrInvalidPrizeStructure)
+		}
+	default:
+		return fmt.Errorf("%w: unknown pool_type %q", ErrInvalidPrizeStructure, s.PoolType)
+	}
+
+	if s.RakePercent < 0 || s.RakePercent >= 100 {
+		return fmt.Errorf("%w: rake_percent must be between 0 and 100", ErrInvalidPrizeStructure)
+	}
+
+	if len(s.Ranks) == 0 {
+		return fmt.Errorf("%w: at least one rank range is required", ErrInvalidPrizeStructure)
+	}
+
+	totalPercent := 0.0
+	for i, r := range s.Ranks {
+		if r.From < 1 || r.To < r.From {
+			return fmt.Errorf("%w: invalid rank range %d-%d", ErrInvalidPrizeStructure, r.From, r.To)
+		}
+		if i > 0 && r.From <= s.Ranks[i-1].To {
+			return fmt.Errorf("%w: rank range %d-%d overlaps %d-%d", ErrInvalidPrizeStructure, r.From, r.To, s.Ranks[i-1].From, s.Ranks[i-1].To)
+		}
+		if r.Amount < 0 || r.Percent < 0 || (r.Amount > 0) == (r.Percent > 0) {
+			return fmt.Errorf("%w: rank range %d-%d needs either an amount or a percent", ErrInvalidPrizeStructure, r.From, r.To)
+		}
+		totalPercent += r.Percent * float64(r.To-r.From+1)
+	}
+
+	if totalPercent > 100 {
+		return fmt.Errorf("%w: percentages add up to %.2f%% of the pool", ErrInvalidPrizeStructure, totalPercent)
+	}
+
+	if s.PoolType == PrizePoolGuaranteed {
+		if payout := e.totalPayout(s.GuaranteedAmount, e.PaidRanks()); payout > s.GuaranteedAmount+0.005 {
+			return fmt.Errorf("%w: prizes of %.2f exceed the guaranteed pool of %.2f", ErrInvalidPrizeStructure, payout, s.GuaranteedAmount)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that the structure fits a contest with the given entry fee
+// and capacity: every paid rank can be reached and a full contest funds all
+// prizes
+func (e *PrizeEngine) Validate(entryFee float64, maxEntries int) error {
+	if paidRanks := e.PaidRanks(); paidRanks > maxEntries {
+		return fmt.Errorf("%w: prizes are paid down to rank %d but the contest only has %d entries", ErrInvalidPrizeStructure, paidRanks, maxEntries)
+	}
+
+	if e.structure.PoolType == PrizePoolEntryBased {
+		pool := e.Pool(entryFee, maxEntries)
+		if payout := e.totalPayout(pool, maxEntries); payout > pool+0.005 {
+			return fmt.Errorf("%w: prizes of %.2f exceed the full-contest pool of %.2f", ErrInvalidPrizeStructure, payout, pool)
+		}
+	}
+
+	return nil
+}
+
+// Structure returns the parsed prize structure
+func (e *PrizeEngine) Structure() models.PrizeStructure {
+	return e.structure
+}
+
+// JSON returns the canonical JSON form of the structure for storage
+func (e *PrizeEngine) JSON() (string, error) {
+	data, err := json.Marshal(e.structure)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize prize structure: %w", err)
+	}
+	return string(data), nil
+}
+
+// PaidRanks returns the lowest rank that can win a prize
+func (e *PrizeEngine) PaidRanks() int {
+	return e.structure.Ranks[len(e.structure.Ranks)-1].To
+}
+
+// Pool returns the prize pool for the given number of participants
+func (e *PrizeEngine) Pool(entryFee float64, participants int) float64 {
+	if e.structure.PoolType == PrizePoolGuaranteed {
+		return e.structure.GuaranteedAmount
+	}
+	collections := entryFee * float64(participants)
+	return roundCurrency(collections * (100 - e.structure.RakePercent) / 100)
+}
+
+// Rake returns what the platform keeps from entry fees. It is negative when
+// a guaranteed pool is larger than the fees collected.
+func (e *PrizeEngine) Rake(entryFee float64, participants int) float64 {
+	return roundCurrency(entryFee*float64(participants) - e.Pool(entryFee, participants))
+}
+
+// PrizeForRank returns the prize for a rank out of the given pool
+func (e *PrizeEngine) PrizeForRank(rank int, pool float64) float64 {
+	ranks := e.structure.Ranks
+	i := sort.Search(len(ranks), func(i int) bool { return ranks[i].To >= rank })
+	if i == len(ranks) || rank < ranks[i].From {
+		return 0
+	}
+	if ranks[i].Amount > 0 {
+		return ranks[i].Amount
+	}
+	return pool * ranks[i].Percent / 100
+}
+
+// Distribute returns the prize for each paid rank when the contest has the
+// given number of participants. If an entry-based pool does not cover its
+// fixed prizes, every prize is reduced in proportion and the rounding
+// remainder goes to the last paid rank. Prizes never add up to more than the
+// pool.
+func (e *PrizeEngine) Distribute(entryFee float64, participants int) map[int]float64 {
+	prizes := make(map[int]float64)
+	if participants <= 0 {
+		return prizes
+	}
+
+	pool := e.Pool(entryFee, participants)
+	paidRanks := e.PaidRanks()
+	if participants < paidRanks {
+		paidRanks = participants
+	}
+
+	scaled := false
+	scale := 1.0
+	if payout := e.totalPayout(pool, paidRanks); e.structure.PoolType == PrizePoolEntryBased && payout > pool && payout > 0 {
+		scaled = true
+		scale = pool / payout
+	}
+
+	// Work in paise so the cap and the remainder are exact
+	poolPaise := toPaise(pool)
+	paidPaise := int64(0)
+	lastRank := 0
+	for _, r := range e.structure.Ranks {
+		if r.From > paidRanks {
+			break
+		}
+		to := r.To
+		if to > paidRanks {
+			to = paidRanks
+		}
+		prize := toPaise(e.PrizeForRank(r.From, pool) * scale)
+		for rank := r.From; rank <= to; rank++ {
+			if left := poolPaise - paidPaise; prize > left {
+				prize = left
+			}
+			if prize <= 0 {
+				break
+			}
+			prizes[rank] = float64(prize) / 100
+			paidPaise += prize
+			lastRank = rank
+		}
+	}
+
+	if remainder := poolPaise - paidPaise; scaled && remainder > 0 && lastRank > 0 {
+		prizes[lastRank] = float64(toPaise(prizes[lastRank])+remainder) / 100
+	}
+
+	return prizes
+}
+
+// totalPayout adds up the prizes for ranks 1 to paidRanks out of pool
+func (e *PrizeEngine) totalPayout(pool float64, paidRanks int) float64 {
+	total := 0.0
+	for _, r := range e.structure.Ranks {
+		if r.From > paidRanks {
+			break
+		}
+		to := r.To
+		if to > paidRanks {
+			to = paidRanks
+		}
+		total += e.PrizeForRank(r.From, pool) * float64(to-r.From+1)
+	}
+	return total
+}
+
+// validatePrizePool parses and validates a prize structure for a contest with
+// the given entry fee and capacity, and returns its canonical JSON
+func validatePrizePool(raw string, entryFee float64, maxEntries int) (string, error) {
+	engine, err := NewPrizeEngine(raw)
+	if err != nil {
+		return "", err
+	}
+	if err := engine.Validate(entryFee, maxEntries); err != nil {
+		return "", err
+	}
+	return engine.JSON()
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"math"
+)
+
+// samePoints reports whether two scores are equal once rounded to the two
+// decimals points are shown with, so float drift never breaks a tie
+func samePoints(a, b float64) bool {
+	return math.Round(a*100) == math.Round(b*100)
+}
+
+// competitionRanks ranks n entries already sorted best first. Entries on the
+// same points share a rank and the next entry skips the shared places
+// (1, 2, 2, 4). tied reports whether an entry shares its rank.
+func competitionRanks(n int, points func(i int) float64) (ranks []int, tied []bool) {
+	ranks = make([]int, n)
+	tied = make([]bool, n)
+
+	for i := 0; i < n; i++ {
+		if i > 0 && samePoints(points(i), points(i-1)) {
+			ranks[i] = ranks[i-1]
+			tied[i] = true
+			tied[i-1] = true
+		} else {
+			ranks[i] = i + 1
+		}
+	}
+
+	return ranks, tied
+}
+
+// splitTiedPrizes returns the prize for each ranked entry. Entries sharing a
+// rank pool the prizes for every place they occupy and split the total
+// evenly, rounded down to whole paise so the split never exceeds the pool.
+func splitTiedPrizes(prizes map[int]float64, ranks []int) []float64 {
+	split := make([]float64, len(ranks))
+
+	for start := 0; start < len(ranks); {
+		end := start + 1
+		for end < len(ranks) && ranks[end] == ranks[start] {
+			end++
+		}
+
+		total := 0.0
+		for place := start + 1; place <= end; place++ {
+			total += prizes[place]
+		}
+
+		share := math.Floor(math.Round(total*100)/float64(end-start)) / 100
+		for i := start; i < end; i++ {
+			split[i] = share
+		}
+
+		start = end
+	}
+
+	return split
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"esports-fantasy-backend/config"
+	"esports-fantasy-backend/internal/models"
+)
+
+const razorpayBaseURL = "https://api.razorpay.com/v1"
+
+// RazorpayGateway collects deposits through Razorpay Checkout. Each deposit
+// is a Razorpay order whose receipt is our transaction ID.
+type RazorpayGateway struct {
+	cfg    *config.Config
+	client *http.Client
+}
+
+type razorpayOrder struct {
+	ID       string `json:"id"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Receipt  string `json:"receipt"`
+	Status   string `json:"status"`
+}
+
+type razorpayPayment struct {
+	ID      string `json:"id"`
+	OrderID string `json:"order_id"`
+	Amount  int64  `json:"amount"`
+	Status  string `json:"status"` // created, authorized, captured, refunded, failed
+}
+
+type razorpayRefund struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+type razorpayError struct {
+	Error struct {
+		Code        string `json:"code"`
+		Description string `json:"description"`
+	} `json:"error"`
+}
+
+func NewRazorpayGateway(cfg *config.Config) *RazorpayGateway {
+	return &RazorpayGateway{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (g *RazorpayGateway) Name() string {
+	return GatewayRazorpay
+}
+
+func (g *RazorpayGateway) Initiate(ctx context.Context, req *PaymentInitiation) (*PaymentSession, error) {
+	var order razorpayOrder
+	err := g.call(ctx, http.MethodPost, "/orders", map[string]interface{}{
+		"amount":   toPaise(req.Amount),
+		"currency": "INR",
+		"receipt":  req.TransactionID.String(),
+	}, &order)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create razorpay order: %w", err)
+	}
+
+	return &PaymentSession{
+		TransactionID: req.TransactionID,
+		Gateway:       GatewayRazorpay,
+		OrderID:       order.ID,
+		PublicKey:     g.cfg.RazorpayKeyID,
+		Data: map[string]interface{}{
+			"id":       order.ID,
+			"entity":   "order",
+			"amount":   order.Amount,
+			"currency": order.Currency,
+			"receipt":  order.Receipt,
+			"status":   order.Status,
+		},
+	}, nil
+}
+
+// Status looks at the order's payments; any captured payment completes the
+// deposit
+func (g *RazorpayGateway) Status(ctx context.Context, transaction *models.Transaction) (*PaymentStatus, error) {
+	var payments struct {
+		Items []razorpayPayment `json:"items"`
+	}
+	err := g.call(ctx, http.MethodGet, fmt.Sprintf("/orders/%s/payments", transaction.OrderID), nil, &payments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get razorpay payments: %w", err)
+	}
+
+	status := &PaymentStatus{
+		TransactionID: transaction.ID,
+		OrderID:       transaction.OrderID,
+		Status:        DepositStatusPending,
+	}
+	failed := 0
+	for _, payment := range payments.Items {
+		switch payment.Status {
+		case "captured":
+			status.PaymentID = payment.ID
+			status.Amount = float64(payment.Amount) / 100
+			status.Status = DepositStatusCompleted
+			status.GatewayState = payment.Status
+			return status, nil
+		case "failed":
+			failed++
+			status.PaymentID = payment.ID
+			status.GatewayState = payment.Status
+		}
+	}
+	if len(payments.Items) > 0 && failed == len(payments.Items) {
+		status.Status = DepositStatusFailed
+	}
+	return status, nil
+}
+
+// VerifyCallback checks the Checkout signature, an HMAC of the order and
+// payment IDs with the key secret
+func (g *RazorpayGateway) VerifyCallback(callback *PaymentCallback) (*PaymentStatus, error) {
+	if callback.OrderID == "" || callback.PaymentID == "" {
+		return nil, fmt.Errorf("%w: order and payment IDs are required", ErrInvalidCallback)
+	}
+
+	mac := hmac.New(sha256.New, []byte(g.cfg.RazorpaySecret))
+	mac.Write([]byte(callback.OrderID + "|" + callback.PaymentID))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(callback.Signature)) {
+		return nil, fmt.Errorf("%w: invalid signature", ErrInvalidCallback)
+	}
+
+	return &PaymentStatus{
+		OrderID:      callback.OrderID,
+		PaymentID:    callback.PaymentID,
+		Status:       DepositStatusCompleted,
+		GatewayState: "captured",
+	}, nil
+}
+
+func (g *RazorpayGateway) Refund(ctx context.Context, req *RefundRequest) (*RefundResult, error) {
+	var refund razorpayRefund
+	err := g.call(ctx, http.MethodPost, fmt.Sprintf("/payments/%s/refund", req.PaymentID), map[string]interface{}{
+		"amount":  toPaise(req.Amount),
+		"receipt": req.RefundID.String(),
+	}, &refund)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refund razorpay payment: %w", err)
+	}
+	if refund.Status == "failed" {
+		return nil, fmt.Errorf("razorpay refund %s failed", refund.ID)
+	}
+
+	return &RefundResult{Reference: refund.ID}, nil
+}
+
+func (g *RazorpayGateway) call(ctx context.Context, method, path string, body, out interface{}) error {
+	reader := bytes.NewReader(nil)
+	if body != nil {
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(bodyJSON)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, razorpayBaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.SetBasicAuth(g.cfg.RazorpayKeyID, g.cfg.RazorpaySecret)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr razorpayError
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("razorpay error %d %s: %s", resp.StatusCode, apiErr.Error.Code, apiErr.Error.Description)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ReferralService interface {
@@ -43,14 +45,18 @@ type ReferralEntry struct {
 }
 
 type referralService struct {
-	userRepo repository.UserRepository
-	config   *config.Config
+	db            *gorm.DB
+	userRepo      repository.UserRepository
+	ledgerService LedgerService
+	config        *config.Config
 }
 
-func NewReferralService(userRepo repository.UserRepository, config *config.Config) ReferralService {
+func NewReferralService(db *gorm.DB, userRepo repository.UserRepository, ledgerService LedgerService, config *config.Config) ReferralService {
 	return &referralService{
-		userRepo: userRepo,
-		config:   config,
+		db:            db,
+		userRepo:      userRepo,
+		ledgerService: ledgerService,
+		config:        config,
 	}
 }
 
@@ -107,20 +113,57 @@ func (s *referralService) ApplyReferralCode(userID uuid.UUID, referralCode strin
 	referralBonus := 50.0 // ₹50 bonus for referrer
 	newUserBonus := 25.0  // ₹25 bonus for new user
 
-	// Update referred user
-	user.ReferredBy = &referrer.ID
-	user.WalletBalance += newUserBonus
-	if err := s.userRepo.UpdateUser(user); err != nil {
-		return fmt.Errorf("failed to update referred user: %w", err)
-	}
+	// Link the users and pay both bonuses together, so a failure leaves the
+	// referral unapplied and safe to retry
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, "id = ?", userID).Error; err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if locked.ReferredBy != nil {
+			return fmt.Errorf("user has already used a referral code")
+		}
 
-	// Update referrer
-	referrer.ReferralBonus += referralBonus
-	referrer.WalletBalance += referralBonus
-	if err := s.userRepo.UpdateUser(referrer); err != nil {
-		return fmt.Errorf("failed to update referrer: %w", err)
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("referred_by", referrer.ID).Error; err != nil {
+			return fmt.Errorf("failed to update referred user: %w", err)
+		}
+		err := tx.Model(&models.User{}).Where("id = ?", referrer.ID).
+			Update("referral_bonus", gorm.Expr("referral_bonus + ?", referralBonus)).Error
+		if err != nil {
+			return fmt.Errorf("failed to update referrer: %w", err)
+		}
+
+		// Both bonuses are keyed on the referred user so they are paid once
+		if err := s.creditBonus(tx, userID, newUserBonus, "referred", userID, "Referral sign-up bonus"); err != nil {
+			return err
+		}
+		return s.creditBonus(tx, referrer.ID, referralBonus, "referrer", userID, "Referral reward")
+	})
+}
+
+// creditBonus posts a referral bonus to the user's bonus bucket and records
+// the transaction, in the caller's database transaction
+func (s *referralService) creditBonus(tx *gorm.DB, userID uuid.UUID, amount float64, role string, referredUserID uuid.UUID, description string) error {
+	transaction := &models.Transaction{
+		ID:              uuid.New(),
+		UserID:          userID,
+		Amount:          amount,
+		Type:            "referral_bonus",
+		Status:          "completed",
+		RelatedEntityID: &referredUserID,
+		Description:     description,
+	}
+	SingleBucket(WalletBucketBonus, amount).ApplyTo(transaction)
+
+	err := s.ledgerService.RecordBonus(tx, userID, amount,
+		fmt.Sprintf("referral_bonus:%s:%s", role, referredUserID), description)
+	if err != nil {
+		return fmt.Errorf("failed to credit referral bonus: %w", err)
 	}
 
+	if err := tx.Create(transaction).Error; err != nil {
+		return fmt.Errorf("failed to record referral bonus: %w", err)
+	}
 	return nil
 }
 
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PreviewScoring rescores a completed match with a candidate ruleset and
+// reports how player points, fantasy team totals and contest leaderboards
+// would change. It only reads; nothing is written to Postgres or Redis.
+func (s *scoringService) PreviewScoring(req *models.ScoringPreviewRequest) (*models.ScoringPreviewResult, error) {
+	var match models.Match
+	if err := s.db.First(&match, "id = ?", req.MatchID).Error; err != nil {
+		return nil, fmt.Errorf("match not found")
+	}
+	if !strings.EqualFold(match.Status, "completed") {
+		return nil, fmt.Errorf("match %s is not completed", req.MatchID)
+	}
+
+	rules := make([]models.GameScoringRule, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		actionType := normalizeActionType(rule.ActionType)
+		if _, ok := actionCount(actionType, &models.PlayerMatchStats{}); !ok {
+			return nil, fmt.Errorf("unsupported action type: %s", rule.ActionType)
+		}
+		rules = append(rules, models.GameScoringRule{
+			ActionType: actionType,
+			Points:     rule.Points,
+			IsActive:   true,
+		})
+	}
+	engine := NewScoringRuleEngine(rules)
+
+	var stats []models.PlayerMatchStats
+	if err := s.db.Preload("Player").Where("match_id = ?", req.MatchID).Find(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to get player stats: %w", err)
+	}
+
+	result := &models.ScoringPreviewResult{
+		MatchID:  req.MatchID,
+		Players:  make([]models.PlayerPointsPreview, 0, len(stats)),
+		Contests: []models.ContestScoringPreview{},
+	}
+
+	previewByPlayer := make(map[uuid.UUID]float64, len(stats))
+	for i := range stats {
+		items := engine.Breakdown(&stats[i])
+		points := sumBreakdown(items)
+		previewByPlayer[stats[i].PlayerID] = points
+
+		result.Players = append(result.Players, models.PlayerPointsPreview{
+			PlayerID:      stats[i].PlayerID,
+			PlayerName:    stats[i].Player.Name,
+			CurrentPoints: stats[i].TotalPoints,
+			PreviewPoints: points,
+			Delta:         points - stats[i].TotalPoints,
+			Items:         items,
+		})
+	}
+
+	sort.Slice(result.Players, func(i, j int) bool {
+		return result.Players[i].PreviewPoints > result.Players[j].PreviewPoints
+	})
+
+	var contests []models.Contest
+	if err := s.db.Where("match_id = ?", req.MatchID).Order("created_at ASC").Find(&contests).Error; err != nil {
+		return nil, fmt.Errorf("failed to get contests: %w", err)
+	}
+
+	for _, contest := range contests {
+		var teams []models.FantasyTeam
+		if err := s.db.Preload("Players").Where("contest_id = ?", contest.ID).Find(&teams).Error; err != nil {
+			return nil, fmt.Errorf("failed to get fantasy teams: %w", err)
+		}
+
+		leaderboard := make([]models.FantasyTeamPreview, 0, len(teams))
+		for _, team := range teams {
+			previewPoints := 0.0
+			for _, fantasyPlayer := range team.Players {
+				_, multiplier := fantasyPlayerMultiplier(fantasyPlayer)
+				previewPoints += previewByPlayer[fantasyPlayer.PlayerID] * multiplier
+			}
+
+			leaderboard = append(leaderboard, models.FantasyTeamPreview{
+				FantasyTeamID: team.ID,
+				TeamName:      team.TeamName,
+				UserID:        team.UserID,
+				CurrentPoints: team.TotalPoints,
+				PreviewPoints: previewPoints,
+			})
+		}
+
+		rankPreviewTeams(leaderboard, func(t *models.FantasyTeamPreview) float64 { return t.CurrentPoints }, func(t *models.FantasyTeamPreview, rank int) { t.CurrentRank = rank })
+		rankPreviewTeams(leaderboard, func(t *models.FantasyTeamPreview) float64 { return t.PreviewPoints }, func(t *models.FantasyTeamPreview, rank int) { t.PreviewRank = rank })
+
+		contestPreview := models.ContestScoringPreview{
+			ContestID:   contest.ID,
+			ContestName: contest.Name,
+			Leaderboard: leaderboard,
+		}
+		for i := range leaderboard {
+			leaderboard[i].RankChange = leaderboard[i].CurrentRank - leaderboard[i].PreviewRank
+			if leaderboard[i].RankChange != 0 {
+				contestPreview.TeamsMoved++
+			}
+		}
+
+		result.Contests = append(result.Contests, contestPreview)
+	}
+
+	return result, nil
+}
+
+// rankPreviewTeams sorts teams by the given points, best first, and assigns
+// ranks where equal points share a rank
+func rankPreviewTeams(teams []models.FantasyTeamPreview, points func(*models.FantasyTeamPreview) float64, setRank func(*models.FantasyTeamPreview, int)) {
+	sort.SliceStable(teams, func(i, j int) bool {
+		return points(&teams[i]) > points(&teams[j])
+	})
+
+	rank := 0
+	for i := range teams {
+		if i == 0 || !samePoints(points(&teams[i]), points(&teams[i-1])) {
+			rank = i + 1
+		}
+		setRank(&teams[i], rank)
+	}
+}
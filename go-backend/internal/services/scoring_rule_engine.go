@@ -0,0 +1,260 @@
+package services
+
+import (
+	"encoding/json"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Action types understood by the scoring rule engine. Admins create
+// GameScoringRule rows using one of these as the ActionType.
+const (
+	ActionKill           = "kill"
+	ActionKnockout       = "knockout"
+	ActionRevive         = "revive"
+	ActionSurvivalMinute = "survival_minute"
+	ActionNotKnocked     = "not_knocked"
+	ActionMVP            = "mvp"
+	ActionTeamKill       = "team_kill"
+)
+
+// Placement points are stored as one action type per placement, e.g.
+// "placement_1" for a chicken dinner
+const ActionPlacementPrefix = "placement_"
+
+// Order in which actions appear in a player's points breakdown. Placement
+// points always come last.
+var scoringActionOrder = []string{
+	ActionKill,
+	ActionKnockout,
+	ActionRevive,
+	ActionSurvivalMinute,
+	ActionNotKnocked,
+	ActionMVP,
+	ActionTeamKill,
+}
+
+// Survival time after which a player is assumed not to have been knocked
+const NotKnockedSurvivalMinutes = 20
+
+// Default BGMI points per action, used for games without scoring rules of
+// their own
+var defaultActionPoints = map[string]float64{
+	ActionKill:           PointsPerKill,
+	ActionKnockout:       PointsPerKnockout,
+	ActionRevive:         PointsPerRevive,
+	ActionSurvivalMinute: PointsPerSurvivalMinute,
+	ActionNotKnocked:     PointsNotKnocked,
+	ActionMVP:            PointsMVP,
+	ActionTeamKill:       PointsTeamKillPenalty,
+}
+
+// Default BGMI squad placement points, used alongside the default scoring
+// rules for games without any configuration
+var DefaultPlacementPoints = map[int]float64{
+	1: 10,
+	2: 6,
+	3: 5,
+	4: 4,
+	5: 3,
+	6: 2,
+	7: 1,
+	8: 1,
+}
+
+// PlacementActionType returns the action type that scores a squad placement
+func PlacementActionType(placement int) string {
+	return ActionPlacementPrefix + strconv.Itoa(placement)
+}
+
+// placementScoringRules turns a game's placement table into scoring rules so
+// it is evaluated and snapshotted alongside the game's other rules
+func placementScoringRules(points []models.GamePlacementPoint) []models.GameScoringRule {
+	rules := make([]models.GameScoringRule, 0, len(points))
+	for _, point := range points {
+		rules = append(rules, models.GameScoringRule{
+			GameID:      point.GameID,
+			ActionType:  PlacementActionType(point.Placement),
+			Points:      point.Points,
+			Description: fmt.Sprintf("Squad placement #%d", point.Placement),
+			IsActive:    true,
+		})
+	}
+	return rules
+}
+
+// withPlacementRules adds a game's placement table to its active scoring
+// rules. A game with placement points but no action rules keeps the default
+// action points, so setting placements alone never zeroes kills, knocks and
+// revives.
+func withPlacementRules(rules []models.GameScoringRule, placements []models.GamePlacementPoint) []models.GameScoringRule {
+	if len(rules) == 0 && len(placements) > 0 {
+		for _, actionType := range scoringActionOrder {
+			rules = append(rules, models.GameScoringRule{
+				GameID:      placements[0].GameID,
+				ActionType:  actionType,
+				Points:      defaultActionPoints[actionType],
+				Description: fmt.Sprintf("Default %s points", strings.ReplaceAll(actionType, "_", " ")),
+				IsActive:    true,
+			})
+		}
+	}
+	return append(rules, placementScoringRules(placements)...)
+}
+
+// ScoringRuleEngine evaluates a game's scoring rules against player stats
+type ScoringRuleEngine struct {
+	rules map[string]float64
+}
+
+// NewScoringRuleEngine builds an engine from a game's active scoring rules
+func NewScoringRuleEngine(rules []models.GameScoringRule) *ScoringRuleEngine {
+	engine := &ScoringRuleEngine{rules: make(map[string]float64)}
+	for _, rule := range rules {
+		if !rule.IsActive {
+			continue
+		}
+		actionType := normalizeActionType(rule.ActionType)
+		if _, ok := actionCount(actionType, &models.PlayerMatchStats{}); !ok {
+			log.Printf("⚠️ Ignoring scoring rule %s with unsupported action type: %s", rule.ID, rule.ActionType)
+			continue
+		}
+		engine.rules[actionType] += rule.Points
+	}
+	return engine
+}
+
+// NewScoringRuleEngineFromRuleset builds an engine from a versioned ruleset
+// snapshot, such as the one pinned to a contest when it locks
+func NewScoringRuleEngineFromRuleset(ruleset *models.ScoringRuleset) (*ScoringRuleEngine, error) {
+	var snapshot []models.ScoringRulesetRule
+	if err := json.Unmarshal([]byte(ruleset.Rules), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse scoring ruleset v%d: %w", ruleset.Version, err)
+	}
+
+	if len(snapshot) == 0 {
+		return DefaultScoringRuleEngine(), nil
+	}
+
+	rules := make([]models.GameScoringRule, 0, len(snapshot))
+	for _, rule := range snapshot {
+		rules = append(rules, models.GameScoringRule{
+			ActionType: rule.ActionType,
+			Points:     rule.Points,
+			IsActive:   true,
+		})
+	}
+
+	return NewScoringRuleEngine(rules), nil
+}
+
+// DefaultScoringRuleEngine uses the built-in BGMI points table and is only
+// meant for games that have no scoring rules configured
+func DefaultScoringRuleEngine() *ScoringRuleEngine {
+	engine := &ScoringRuleEngine{rules: make(map[string]float64, len(defaultActionPoints)+len(DefaultPlacementPoints))}
+	for actionType, points := range defaultActionPoints {
+		engine.rules[actionType] = points
+	}
+	for placement, points := range DefaultPlacementPoints {
+		engine.rules[PlacementActionType(placement)] = points
+	}
+	return engine
+}
+
+// Evaluate returns the fantasy points earned by the given stats
+func (e *ScoringRuleEngine) Evaluate(stats *models.PlayerMatchStats) float64 {
+	return sumBreakdown(e.Breakdown(stats))
+}
+
+// Breakdown itemises the points earned by the given stats, one line per
+// action that actually occurred
+func (e *ScoringRuleEngine) Breakdown(stats *models.PlayerMatchStats) []models.PointsBreakdownItem {
+	items := []models.PointsBreakdownItem{}
+	for _, actionType := range scoringActionOrder {
+		pointsPerAction, ok := e.rules[actionType]
+		if !ok {
+			continue
+		}
+
+		count, _ := actionCount(actionType, stats)
+		if count == 0 {
+			continue
+		}
+
+		items = append(items, models.PointsBreakdownItem{
+			ActionType:      actionType,
+			Count:           count,
+			PointsPerAction: pointsPerAction,
+			Points:          count * pointsPerAction,
+		})
+	}
+
+	// Every player of a squad earns its placement points
+	if stats.Placement > 0 {
+		actionType := PlacementActionType(stats.Placement)
+		if pointsPerAction, ok := e.rules[actionType]; ok && pointsPerAction != 0 {
+			items = append(items, models.PointsBreakdownItem{
+				ActionType:      actionType,
+				Count:           1,
+				PointsPerAction: pointsPerAction,
+				Points:          pointsPerAction,
+			})
+		}
+	}
+
+	return items
+}
+
+func sumBreakdown(items []models.PointsBreakdownItem) float64 {
+	points := 0.0
+	for _, item := range items {
+		points += item.Points
+	}
+	return points
+}
+
+// actionCount maps an action type to the number of times it occurred
+func actionCount(actionType string, stats *models.PlayerMatchStats) (float64, bool) {
+	switch actionType {
+	case ActionKill:
+		return float64(stats.Kills), true
+	case ActionKnockout:
+		return float64(stats.Knockouts), true
+	case ActionRevive:
+		return float64(stats.Revives), true
+	case ActionSurvivalMinute:
+		return float64(stats.SurvivalTimeMinutes), true
+	case ActionNotKnocked:
+		if stats.SurvivalTimeMinutes > NotKnockedSurvivalMinutes {
+			return 1, true
+		}
+		return 0, true
+	case ActionMVP:
+		if stats.IsMVP {
+			return 1, true
+		}
+		return 0, true
+	case ActionTeamKill:
+		return float64(stats.TeamKillPenalty), true
+	}
+
+	if strings.HasPrefix(actionType, ActionPlacementPrefix) {
+		placement, err := strconv.Atoi(strings.TrimPrefix(actionType, ActionPlacementPrefix))
+		if err != nil || placement < 1 {
+			return 0, false
+		}
+		if stats.Placement == placement {
+			return 1, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func normalizeActionType(actionType string) string {
+	return strings.ToLower(strings.TrimSpace(actionType))
+}
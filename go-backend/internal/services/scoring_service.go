@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"esports-fantasy-backend/internal/models"
+	"esports-fantasy-backend/internal/repository"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
@@ -14,23 +16,40 @@ import (
 
 type ScoringService interface {
 	UpdatePlayerStats(matchID, playerID uuid.UUID, stats *models.UpdateStatsRequest) error
-	CalculatePlayerPoints(stats *models.PlayerMatchStats) float64
+	SetMatchPlacements(matchID uuid.UUID, results []models.MatchPlacementEntry) ([]models.MatchTeamPlacement, error)
+	GetMatchPlacements(matchID uuid.UUID) ([]models.MatchTeamPlacement, error)
+	ImportPlayerStats(matchID uuid.UUID, rows []models.PlayerStatsImportRow, dryRun bool) (*models.StatsImportResult, error)
+	RecordMatchEvent(matchID uuid.UUID, req *models.LiveMatchUpdateRequest, source string) (*models.MatchStatEvent, error)
+	CorrectMatchEvent(eventID uuid.UUID, reason string) (*models.MatchStatEvent, error)
+	GetMatchEvents(matchID uuid.UUID, playerID *uuid.UUID) ([]models.MatchStatEvent, error)
+	RebuildPlayerStats(matchID, playerID uuid.UUID) (*models.PlayerMatchStats, error)
+	CalculatePlayerPoints(stats *models.PlayerMatchStats) (float64, error)
 	RecalculateFantasyTeamScores(matchID uuid.UUID) error
+	ContestTeamTotals(contestID uuid.UUID) (map[uuid.UUID]float64, error)
+	GetPlayerPointsBreakdown(matchID, playerID uuid.UUID, contestID *uuid.UUID) (*models.PlayerPointsBreakdown, error)
+	GetFantasyTeamPointsBreakdown(teamID uuid.UUID) (*models.FantasyTeamPointsBreakdown, error)
+	PreviewScoring(req *models.ScoringPreviewRequest) (*models.ScoringPreviewResult, error)
 }
 
 type scoringService struct {
-	db  *gorm.DB
-	rdb *redis.Client
+	db              *gorm.DB
+	rdb             *redis.Client
+	scoringRuleRepo repository.GameScoringRuleRepository
+	rulesetRepo     repository.ScoringRulesetRepository
+	placementRepo   repository.PlacementRepository
 }
 
-func NewScoringService(db *gorm.DB, rdb *redis.Client) ScoringService {
+func NewScoringService(db *gorm.DB, rdb *redis.Client, scoringRuleRepo repository.GameScoringRuleRepository, rulesetRepo repository.ScoringRulesetRepository, placementRepo repository.PlacementRepository) ScoringService {
 	return &scoringService{
-		db:  db,
-		rdb: rdb,
+		db:              db,
+		rdb:             rdb,
+		scoringRuleRepo: scoringRuleRepo,
+		rulesetRepo:     rulesetRepo,
+		placementRepo:   placementRepo,
 	}
 }
 
-// Scoring rules for eSports fantasy
+// Default scoring rules, used for games without GameScoringRule records
 const (
 	PointsPerKill           = 10.0
 	PointsPerKnockout       = 6.0
@@ -43,135 +62,415 @@ const (
 	ViceCaptainMultiplier   = 1.5
 )
 
+// UpdatePlayerStats sets a player's stats for a match. The overwrite is
+// recorded as delta events so the event log stays the source of truth.
 func (s *scoringService) UpdatePlayerStats(matchID, playerID uuid.UUID, stats *models.UpdateStatsRequest) error {
-	// Start database transaction
-	tx := s.db.Begin()
-	if tx.Error != nil {
-		return fmt.Errorf("failed to start transaction: %w", tx.Error)
+	target := models.PlayerMatchStats{
+		MatchID:             matchID,
+		PlayerID:            playerID,
+		Kills:               stats.Kills,
+		Revives:             stats.Revives,
+		Knockouts:           stats.Knockouts,
+		SurvivalTimeMinutes: stats.SurvivalTimeMinutes,
+		IsMVP:               stats.IsMVP,
+		TeamKillPenalty:     stats.TeamKillPenalty,
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+
+	var playerStats *models.PlayerMatchStats
+	var deltas []teamPointsDelta
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		playerStats, deltas, err = s.setPlayerStats(tx, &target)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publishTeamPointsDeltas(matchID, deltas)
+
+	log.Printf("✅ Updated stats for player %s in match %s: %.2f points", playerID, matchID, playerStats.TotalPoints)
+
+	return nil
+}
+
+// setPlayerStats records the events needed to bring a player's folded stats
+// to the target values and rebuilds the stats row
+func (s *scoringService) setPlayerStats(tx *gorm.DB, target *models.PlayerMatchStats) (*models.PlayerMatchStats, []teamPointsDelta, error) {
+	events, err := s.loadPlayerEvents(tx, target.MatchID, target.PlayerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current := foldMatchStatEvents(target.MatchID, target.PlayerID, events)
+	for _, event := range statDeltaEvents(&current, target, mvpCount(events), EventSourceAdmin) {
+		event := event
+		if err := tx.Create(&event).Error; err != nil {
+			return nil, nil, fmt.Errorf("failed to record stat event: %w", err)
 		}
-	}()
+	}
+
+	return s.rebuildPlayerStats(tx, target.MatchID, target.PlayerID)
+}
+
+// RecordMatchEvent appends a single live stat event and refolds the
+// player's stats for the match
+func (s *scoringService) RecordMatchEvent(matchID uuid.UUID, req *models.LiveMatchUpdateRequest, source string) (*models.MatchStatEvent, error) {
+	actionType := normalizeActionType(req.ActionType)
+	if !statEventActionTypes[actionType] {
+		return nil, fmt.Errorf("unsupported action type: %s", req.ActionType)
+	}
+
+	event := &models.MatchStatEvent{
+		MatchID:    matchID,
+		PlayerID:   req.PlayerID,
+		ActionType: actionType,
+		Value:      req.Value,
+		OccurredAt: req.Timestamp,
+		Source:     source,
+	}
+	if event.Value == 0 {
+		event.Value = 1
+	}
+	// MVP is a flag, so it is only ever awarded or taken back once at a time
+	if actionType == ActionMVP && event.Value != 1 && event.Value != -1 {
+		return nil, fmt.Errorf("mvp events must have a value of 1 or -1")
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	var deltas []teamPointsDelta
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Seed the log from rows written before events existed
+		if _, err := s.loadPlayerEvents(tx, matchID, req.PlayerID); err != nil {
+			return err
+		}
+
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to record stat event: %w", err)
+		}
+
+		var err error
+		_, deltas, err = s.rebuildPlayerStats(tx, matchID, req.PlayerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishTeamPointsDeltas(matchID, deltas)
+
+	return event, nil
+}
+
+// CorrectMatchEvent reverses an event by appending a compensating event with
+// the opposite value. Each event can be compensated at most once.
+func (s *scoringService) CorrectMatchEvent(eventID uuid.UUID, reason string) (*models.MatchStatEvent, error) {
+	var original models.MatchStatEvent
+	if err := s.db.First(&original, "id = ?", eventID).Error; err != nil {
+		return nil, fmt.Errorf("event not found: %w", err)
+	}
+
+	if original.CompensatesEventID != nil {
+		return nil, fmt.Errorf("event %s is already a correction", eventID)
+	}
+
+	correction := &models.MatchStatEvent{
+		MatchID:            original.MatchID,
+		PlayerID:           original.PlayerID,
+		ActionType:         original.ActionType,
+		Value:              -original.Value,
+		OccurredAt:         original.OccurredAt,
+		Source:             EventSourceCorrection,
+		CompensatesEventID: &original.ID,
+		Reason:             reason,
+	}
+
+	var deltas []teamPointsDelta
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.MatchStatEvent{}).Where("compensates_event_id = ?", original.ID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check existing corrections: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("event %s has already been corrected", eventID)
+		}
+
+		if err := tx.Create(correction).Error; err != nil {
+			return fmt.Errorf("failed to record correction: %w", err)
+		}
+
+		var err error
+		_, deltas, err = s.rebuildPlayerStats(tx, original.MatchID, original.PlayerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishTeamPointsDeltas(original.MatchID, deltas)
+
+	return correction, nil
+}
+
+func (s *scoringService) GetMatchEvents(matchID uuid.UUID, playerID *uuid.UUID) ([]models.MatchStatEvent, error) {
+	var events []models.MatchStatEvent
+	query := s.db.Where("match_id = ?", matchID)
+	if playerID != nil {
+		query = query.Where("player_id = ?", *playerID)
+	}
+
+	if err := query.Order("occurred_at ASC, created_at ASC").Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get match events: %w", err)
+	}
+	return events, nil
+}
+
+// RebuildPlayerStats recomputes a player's stat row for a match from scratch
+// by folding the event log
+func (s *scoringService) RebuildPlayerStats(matchID, playerID uuid.UUID) (*models.PlayerMatchStats, error) {
+	var playerStats *models.PlayerMatchStats
+	var deltas []teamPointsDelta
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		playerStats, deltas, err = s.rebuildPlayerStats(tx, matchID, playerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishTeamPointsDeltas(matchID, deltas)
+
+	return playerStats, nil
+}
+
+// loadPlayerEvents returns a player's events for a match. A stat row that
+// predates the event log is first converted into baseline events.
+func (s *scoringService) loadPlayerEvents(tx *gorm.DB, matchID, playerID uuid.UUID) ([]models.MatchStatEvent, error) {
+	if err := lockPlayerStats(tx, matchID, playerID); err != nil {
+		return nil, err
+	}
+
+	var events []models.MatchStatEvent
+	err := tx.Where("match_id = ? AND player_id = ?", matchID, playerID).
+		Order("occurred_at ASC, created_at ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stat events: %w", err)
+	}
+	if len(events) > 0 {
+		return events, nil
+	}
+
+	var legacy models.PlayerMatchStats
+	err = tx.Where("player_id = ? AND match_id = ?", playerID, matchID).First(&legacy).Error
+	if err == gorm.ErrRecordNotFound {
+		return events, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player stats: %w", err)
+	}
+
+	empty := models.PlayerMatchStats{MatchID: matchID, PlayerID: playerID}
+	events = statDeltaEvents(&empty, &legacy, 0, EventSourceBaseline)
+	for i := range events {
+		if err := tx.Create(&events[i]).Error; err != nil {
+			return nil, fmt.Errorf("failed to record baseline stat event: %w", err)
+		}
+	}
+	return events, nil
+}
+
+// rebuildPlayerStats folds the event log into the player's stat row,
+// rescores it and applies the change in points to the fantasy teams holding
+// the player. The returned deltas are mirrored to Redis once the
+// transaction commits.
+func (s *scoringService) rebuildPlayerStats(tx *gorm.DB, matchID, playerID uuid.UUID) (*models.PlayerMatchStats, []teamPointsDelta, error) {
+	if err := lockPlayerStats(tx, matchID, playerID); err != nil {
+		return nil, nil, err
+	}
+
+	var events []models.MatchStatEvent
+	if err := tx.Where("match_id = ? AND player_id = ?", matchID, playerID).Find(&events).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to get stat events: %w", err)
+	}
+	folded := foldMatchStatEvents(matchID, playerID, events)
 
 	// Find or create player match stats
 	var playerStats models.PlayerMatchStats
 	err := tx.Where("player_id = ? AND match_id = ?", playerID, matchID).First(&playerStats).Error
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			playerStats = models.PlayerMatchStats{
-				PlayerID: playerID,
-				MatchID:  matchID,
-			}
-		} else {
-			tx.Rollback()
-			return fmt.Errorf("failed to get player stats: %w", err)
+		if err != gorm.ErrRecordNotFound {
+			return nil, nil, fmt.Errorf("failed to get player stats: %w", err)
+		}
+		playerStats = models.PlayerMatchStats{
+			PlayerID: playerID,
+			MatchID:  matchID,
 		}
 	}
+	previous := playerStats
 
-	// Update stats
-	playerStats.Kills = stats.Kills
-	playerStats.Revives = stats.Revives
-	playerStats.Knockouts = stats.Knockouts
-	playerStats.SurvivalTimeMinutes = stats.SurvivalTimeMinutes
-	playerStats.IsMVP = stats.IsMVP
-	playerStats.TeamKillPenalty = stats.TeamKillPenalty
+	playerStats.Kills = folded.Kills
+	playerStats.Revives = folded.Revives
+	playerStats.Knockouts = folded.Knockouts
+	playerStats.SurvivalTimeMinutes = folded.SurvivalTimeMinutes
+	playerStats.IsMVP = folded.IsMVP
+	playerStats.TeamKillPenalty = folded.TeamKillPenalty
 
-	// Calculate points
-	playerStats.TotalPoints = s.CalculatePlayerPoints(&playerStats)
+	placement, err := playerSquadPlacement(tx, matchID, playerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	playerStats.Placement = placement
 
-	// Save player stats
-	if err := tx.Save(&playerStats).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to save player stats: %w", err)
+	// Calculate points and keep the itemised breakdown alongside them
+	engine, err := s.getRuleEngineForMatch(matchID)
+	if err != nil {
+		return nil, nil, err
 	}
+	breakdown := engine.Breakdown(&playerStats)
+	breakdownJSON, err := json.Marshal(breakdown)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to serialize points breakdown: %w", err)
+	}
+	playerStats.TotalPoints = sumBreakdown(breakdown)
+	playerStats.PointsBreakdown = string(breakdownJSON)
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if err := tx.Save(&playerStats).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to save player stats: %w", err)
 	}
 
-	// Recalculate fantasy team scores asynchronously
-	go func() {
-		if err := s.RecalculateFantasyTeamScores(matchID); err != nil {
-			log.Printf("Error recalculating fantasy team scores: %v", err)
-		}
-	}()
+	deltas, err := s.applyPlayerPointsDelta(tx, &previous, &playerStats)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	log.Printf("✅ Updated stats for player %s in match %s: %.2f points", playerID, matchID, playerStats.TotalPoints)
+	return &playerStats, deltas, nil
+}
 
+// lockPlayerStats serialises writers of one player's match stats until the
+// transaction ends, so concurrent events never fold into a stale row
+func lockPlayerStats(tx *gorm.DB, matchID, playerID uuid.UUID) error {
+	key := matchID.String() + ":" + playerID.String()
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error; err != nil {
+		return fmt.Errorf("failed to lock player stats: %w", err)
+	}
 	return nil
 }
 
-func (s *scoringService) CalculatePlayerPoints(stats *models.PlayerMatchStats) float64 {
-	points := 0.0
+// CalculatePlayerPoints scores stats with the match's rules. Failing to load
+// them is an error rather than a reason to score with the default table.
+func (s *scoringService) CalculatePlayerPoints(stats *models.PlayerMatchStats) (float64, error) {
+	engine, err := s.getRuleEngineForMatch(stats.MatchID)
+	if err != nil {
+		return 0, err
+	}
+	return engine.Evaluate(stats), nil
+}
 
-	// Kill points
-	points += float64(stats.Kills) * PointsPerKill
+// getRuleEngineForMatch loads the active scoring rules and placement points
+// for the match's game (match -> tournament -> game), falling back to the default rules when the
+// game has none configured. Placement points alone keep the default action points.
+func (s *scoringService) getRuleEngineForMatch(matchID uuid.UUID) (*ScoringRuleEngine, error) {
+	var match models.Match
+	if err := s.db.Preload("Tournament").First(&match, "id = ?", matchID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get match: %w", err)
+	}
 
-	// Knockout points
-	points += float64(stats.Knockouts) * PointsPerKnockout
+	if match.Tournament.GameID == uuid.Nil {
+		return DefaultScoringRuleEngine(), nil
+	}
 
-	// Revive points
-	points += float64(stats.Revives) * PointsPerRevive
+	rules, err := s.scoringRuleRepo.GetActiveByGameID(match.Tournament.GameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scoring rules: %w", err)
+	}
 
-	// Survival points
-	points += float64(stats.SurvivalTimeMinutes) * PointsPerSurvivalMinute
+	placements, err := s.placementRepo.GetPointsByGameID(match.Tournament.GameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get placement points: %w", err)
+	}
+	rules = withPlacementRules(rules, placements)
 
-	// MVP bonus
-	if stats.IsMVP {
-		points += PointsMVP
+	if len(rules) == 0 {
+		return DefaultScoringRuleEngine(), nil
 	}
 
-	// Team kill penalty
-	points += float64(stats.TeamKillPenalty) * PointsTeamKillPenalty
+	return NewScoringRuleEngine(rules), nil
+}
 
-	// Not knocked bonus (if survival time is significant, assume not knocked)
-	if stats.SurvivalTimeMinutes > 20 { // Arbitrary threshold for "not knocked"
-		points += PointsNotKnocked
+// getRuleEngineForContest scores with the ruleset version pinned to the
+// contest at lock time, so later rule edits never change its results.
+// Contests that have not locked yet use the game's current rules.
+func (s *scoringService) getRuleEngineForContest(contest *models.Contest) (*ScoringRuleEngine, error) {
+	if contest.ScoringRulesetID == nil {
+		return s.getRuleEngineForMatch(contest.MatchID)
 	}
 
-	return points
+	ruleset, err := s.rulesetRepo.GetByID(*contest.ScoringRulesetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pinned scoring ruleset: %w", err)
+	}
+
+	return NewScoringRuleEngineFromRuleset(ruleset)
 }
 
+// RecalculateFantasyTeamScores recomputes every fantasy team total for the
+// match from scratch. Stat changes are applied incrementally as they happen,
+// so this is only needed to repair totals that have drifted.
 func (s *scoringService) RecalculateFantasyTeamScores(matchID uuid.UUID) error {
 	// Get all fantasy teams for contests related to this match
 	var fantasyTeams []models.FantasyTeam
 	err := s.db.Joins("JOIN contests ON fantasy_teams.contest_id = contests.id").
 		Where("contests.match_id = ?", matchID).
+		Preload("Contest").
 		Preload("Players.Player").
 		Find(&fantasyTeams).Error
 	if err != nil {
 		return fmt.Errorf("failed to get fantasy teams: %w", err)
 	}
 
+	// Load every player's stats for the match once
+	var matchStats []models.PlayerMatchStats
+	if err := s.db.Where("match_id = ?", matchID).Find(&matchStats).Error; err != nil {
+		return fmt.Errorf("failed to get player stats: %w", err)
+	}
+	statsByPlayer := make(map[uuid.UUID]*models.PlayerMatchStats, len(matchStats))
+	for i := range matchStats {
+		statsByPlayer[matchStats[i].PlayerID] = &matchStats[i]
+	}
+
+	// Each contest scores with its own pinned ruleset
+	engines := make(map[uuid.UUID]*ScoringRuleEngine)
+
+	ctx := context.Background()
+	pipe := s.rdb.Pipeline()
+
 	// Update scores for each fantasy team
 	for _, team := range fantasyTeams {
-		totalPoints := 0.0
-
-		for _, fantasyPlayer := range team.Players {
-			// Get player stats for this match
-			var playerStats models.PlayerMatchStats
-			err := s.db.Where("player_id = ? AND match_id = ?", fantasyPlayer.PlayerID, matchID).
-				First(&playerStats).Error
+		engine, ok := engines[team.ContestID]
+		if !ok {
+			engine, err = s.getRuleEngineForContest(&team.Contest)
 			if err != nil {
-				if err != gorm.ErrRecordNotFound {
-					log.Printf("Error getting player stats for %s: %v", fantasyPlayer.PlayerID, err)
-				}
+				log.Printf("Error loading scoring rules for contest %s: %v", team.ContestID, err)
 				continue
 			}
+			engines[team.ContestID] = engine
+		}
 
-			points := playerStats.TotalPoints
+		totalPoints := 0.0
 
-			// Apply multipliers
-			if fantasyPlayer.IsCaptain {
-				points *= CaptainMultiplier
-			} else if fantasyPlayer.IsViceCaptain {
-				points *= ViceCaptainMultiplier
+		for _, fantasyPlayer := range team.Players {
+			playerStats, ok := statsByPlayer[fantasyPlayer.PlayerID]
+			if !ok {
+				continue
 			}
 
-			totalPoints += points
+			// Apply captain/vice-captain multipliers
+			_, multiplier := fantasyPlayerMultiplier(fantasyPlayer)
+			totalPoints += engine.Evaluate(playerStats) * multiplier
 		}
 
 		// Update fantasy team total points
@@ -181,29 +480,208 @@ func (s *scoringService) RecalculateFantasyTeamScores(matchID uuid.UUID) error {
 		}
 
 		// Update leaderboard in Redis
-		ctx := context.Background()
 		leaderboardKey := fmt.Sprintf("leaderboard:%s", team.ContestID)
-		if err := s.rdb.ZAdd(ctx, leaderboardKey, &redis.Z{
+		pipe.ZAdd(ctx, leaderboardKey, &redis.Z{
 			Score:  totalPoints,
 			Member: team.ID.String(),
-		}).Err(); err != nil {
-			log.Printf("Error updating Redis leaderboard: %v", err)
-		}
+		})
 
 		log.Printf("🏆 Updated fantasy team %s (%s): %.2f points", team.ID, team.TeamName, totalPoints)
 	}
 
-	// Publish leaderboard update event
-	updateEvent := map[string]interface{}{
-		"type":     "leaderboard_update",
-		"match_id": matchID.String(),
-	}
-	eventData, _ := json.Marshal(updateEvent)
-	
-	ctx := context.Background()
-	if err := s.rdb.Publish(ctx, "leaderboard_updates", eventData).Err(); err != nil {
-		log.Printf("Error publishing leaderboard update: %v", err)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Error updating Redis leaderboard: %v", err)
 	}
 
+	s.publishLeaderboardUpdate(matchID)
+
 	return nil
-}
\ No newline at end of file
+}
+
+// ContestTeamTotals rescores every fantasy team in the contest from the stored
+// player stats with the contest's ruleset. It only reads, so callers can
+// compare the totals with the live standings before writing anything.
+func (s *scoringService) ContestTeamTotals(contestID uuid.UUID) (map[uuid.UUID]float64, error) {
+	var contest models.Contest
+	if err := s.db.First(&contest, "id = ?", contestID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get contest: %w", err)
+	}
+
+	engine, err := s.getRuleEngineForContest(&contest)
+	if err != nil {
+		return nil, err
+	}
+
+	var teams []models.FantasyTeam
+	if err := s.db.Preload("Players").Where("contest_id = ?", contestID).Find(&teams).Error; err != nil {
+		return nil, fmt.Errorf("failed to get fantasy teams: %w", err)
+	}
+
+	var matchStats []models.PlayerMatchStats
+	if err := s.db.Where("match_id = ?", contest.MatchID).Find(&matchStats).Error; err != nil {
+		return nil, fmt.Errorf("failed to get player stats: %w", err)
+	}
+	points := make(map[uuid.UUID]float64, len(matchStats))
+	for i := range matchStats {
+		points[matchStats[i].PlayerID] = engine.Evaluate(&matchStats[i])
+	}
+
+	totals := make(map[uuid.UUID]float64, len(teams))
+	for _, team := range teams {
+		totalPoints := 0.0
+		for _, fantasyPlayer := range team.Players {
+			_, multiplier := fantasyPlayerMultiplier(fantasyPlayer)
+			totalPoints += points[fantasyPlayer.PlayerID] * multiplier
+		}
+		totals[team.ID] = totalPoints
+	}
+	return totals, nil
+}
+
+// fantasyPlayerMultiplier returns the role and points multiplier of a player
+// within a fantasy team
+func fantasyPlayerMultiplier(fantasyPlayer models.FantasyTeamPlayer) (string, float64) {
+	if fantasyPlayer.IsCaptain {
+		return "captain", CaptainMultiplier
+	}
+	if fantasyPlayer.IsViceCaptain {
+		return "vice_captain", ViceCaptainMultiplier
+	}
+	return "", 1.0
+}
+
+// GetPlayerPointsBreakdown itemises a player's points in a match. With a
+// contest, the points are scored with the contest's pinned ruleset so they
+// match the player's lines in its team breakdowns; otherwise the match's
+// current rules are used.
+func (s *scoringService) GetPlayerPointsBreakdown(matchID, playerID uuid.UUID, contestID *uuid.UUID) (*models.PlayerPointsBreakdown, error) {
+	var contest models.Contest
+	if contestID != nil {
+		if err := s.db.First(&contest, "id = ?", *contestID).Error; err != nil {
+			return nil, fmt.Errorf("failed to get contest: %w", err)
+		}
+		if contest.MatchID != matchID {
+			return nil, fmt.Errorf("contest %s is not on match %s", contest.ID, matchID)
+		}
+	}
+
+	var playerStats models.PlayerMatchStats
+	err := s.db.Preload("Player").
+		Where("player_id = ? AND match_id = ?", playerID, matchID).
+		First(&playerStats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player stats: %w", err)
+	}
+
+	if contestID != nil {
+		engine, err := s.getRuleEngineForContest(&contest)
+		if err != nil {
+			return nil, err
+		}
+
+		items := engine.Breakdown(&playerStats)
+		basePoints := sumBreakdown(items)
+		return &models.PlayerPointsBreakdown{
+			PlayerID:    playerID,
+			PlayerName:  playerStats.Player.Name,
+			MatchID:     matchID,
+			ContestID:   &contest.ID,
+			Items:       items,
+			BasePoints:  basePoints,
+			Multiplier:  1.0,
+			TotalPoints: basePoints,
+		}, nil
+	}
+
+	// Stats saved before breakdowns were stored are itemised on the fly
+	var items []models.PointsBreakdownItem
+	if playerStats.PointsBreakdown != "" {
+		if err := json.Unmarshal([]byte(playerStats.PointsBreakdown), &items); err != nil {
+			return nil, fmt.Errorf("failed to parse points breakdown: %w", err)
+		}
+	} else {
+		engine, err := s.getRuleEngineForMatch(matchID)
+		if err != nil {
+			return nil, err
+		}
+		items = engine.Breakdown(&playerStats)
+	}
+
+	return &models.PlayerPointsBreakdown{
+		PlayerID:    playerID,
+		PlayerName:  playerStats.Player.Name,
+		MatchID:     matchID,
+		Items:       items,
+		BasePoints:  playerStats.TotalPoints,
+		Multiplier:  1.0,
+		TotalPoints: playerStats.TotalPoints,
+	}, nil
+}
+
+// GetFantasyTeamPointsBreakdown itemises a fantasy team's points using the
+// contest's pinned ruleset, so the lines always add up to the team total
+func (s *scoringService) GetFantasyTeamPointsBreakdown(teamID uuid.UUID) (*models.FantasyTeamPointsBreakdown, error) {
+	var team models.FantasyTeam
+	err := s.db.Preload("Contest").
+		Preload("Players.Player").
+		First(&team, "id = ?", teamID).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fantasy team: %w", err)
+	}
+
+	engine, err := s.getRuleEngineForContest(&team.Contest)
+	if err != nil {
+		return nil, err
+	}
+
+	matchID := team.Contest.MatchID
+	playerIDs := make([]uuid.UUID, 0, len(team.Players))
+	for _, fantasyPlayer := range team.Players {
+		playerIDs = append(playerIDs, fantasyPlayer.PlayerID)
+	}
+
+	var stats []models.PlayerMatchStats
+	if len(playerIDs) > 0 {
+		err = s.db.Where("match_id = ? AND player_id IN ?", matchID, playerIDs).Find(&stats).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get player stats: %w", err)
+		}
+	}
+
+	statsByPlayer := make(map[uuid.UUID]*models.PlayerMatchStats, len(stats))
+	for i := range stats {
+		statsByPlayer[stats[i].PlayerID] = &stats[i]
+	}
+
+	breakdown := &models.FantasyTeamPointsBreakdown{
+		FantasyTeamID: team.ID,
+		TeamName:      team.TeamName,
+		ContestID:     team.ContestID,
+		MatchID:       matchID,
+		Players:       []models.PlayerPointsBreakdown{},
+	}
+
+	for _, fantasyPlayer := range team.Players {
+		items := []models.PointsBreakdownItem{}
+		if playerStats, ok := statsByPlayer[fantasyPlayer.PlayerID]; ok {
+			items = engine.Breakdown(playerStats)
+		}
+
+		role, multiplier := fantasyPlayerMultiplier(fantasyPlayer)
+		basePoints := sumBreakdown(items)
+
+		breakdown.Players = append(breakdown.Players, models.PlayerPointsBreakdown{
+			PlayerID:    fantasyPlayer.PlayerID,
+			PlayerName:  fantasyPlayer.Player.Name,
+			MatchID:     matchID,
+			Items:       items,
+			BasePoints:  basePoints,
+			Role:        role,
+			Multiplier:  multiplier,
+			TotalPoints: basePoints * multiplier,
+		})
+		breakdown.TotalPoints += basePoints * multiplier
+	}
+
+	return breakdown, nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"esports-fantasy-backend/config"
 	"esports-fantasy-backend/internal/models"
 	"esports-fantasy-backend/internal/repository"
@@ -46,21 +47,24 @@ type SeasonLeagueEntry struct {
 type seasonLeagueService struct {
 	leagueRepo repository.SeasonLeagueRepository
 	gameRepo   repository.GameRepository
-	userRepo   repository.UserRepository
-	config     *config.Config
+	userRepo      repository.UserRepository
+	ledgerService LedgerService
+	config        *config.Config
 }
 
 func NewSeasonLeagueService(
 	leagueRepo repository.SeasonLeagueRepository,
 	gameRepo repository.GameRepository,
 	userRepo repository.UserRepository,
+	ledgerService LedgerService,
 	config *config.Config,
 ) SeasonLeagueService {
 	return &seasonLeagueService{
-		leagueRepo: leagueRepo,
-		gameRepo:   gameRepo,
-		userRepo:   userRepo,
-		config:     config,
+		leagueRepo:    leagueRepo,
+		gameRepo:      gameRepo,
+		userRepo:      userRepo,
+		ledgerService: ledgerService,
+		config:        config,
 	}
 }
 
@@ -207,20 +211,13 @@ func (s *seasonLeagueService) JoinSeasonLeague(leagueID, userID uuid.UUID) error
 	// Check if league is full (this would require a participants table in a real implementation)
 	// For now, we'll skip this check
 
-	// Get user to check balance
-	user, err := s.userRepo.GetUserByID(userID)
+	// Move the entry fee into escrow; the balance check happens under a lock
+	_, err = s.ledgerService.RecordEntryFee(nil, JournalKindLeagueEntry, userID, league.EntryFee, leagueID, nil,
+		fmt.Sprintf("league_entry:%s:%s", leagueID, userID))
 	if err != nil {
-		return fmt.Errorf("user not found: %w", err)
-	}
-
-	// Check if user has enough balance
-	if user.WalletBalance < league.EntryFee {
-		return fmt.Errorf("insufficient wallet balance")
-	}
-
-	// Deduct entry fee (in a real implementation, this would be a transaction)
-	user.WalletBalance -= league.EntryFee
-	if err := s.userRepo.UpdateUser(user); err != nil {
+		if errors.Is(err, ErrInsufficientFunds) {
+			return err
+		}
 		return fmt.Errorf("failed to deduct entry fee: %w", err)
 	}
 
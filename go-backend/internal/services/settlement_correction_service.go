@@ -0,0 +1,445 @@
+package services
+
+import (
+	"encoding/json"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Settlement correction statuses
+const (
+	CorrectionStatusPending  = "pending"
+	CorrectionStatusApplied  = "applied"
+	CorrectionStatusRejected = "rejected"
+)
+
+// Transaction types posted when a settled contest is corrected. Amounts are
+// always positive; the type gives the direction.
+const (
+	TransactionTypeWinnings      = "winnings"
+	TransactionTypePrizeCredit   = "prize_credit"
+	TransactionTypePrizeClawback = "prize_clawback"
+)
+
+// Reason codes accepted for settlement corrections
+var correctionReasonCodes = map[string]bool{
+	"stat_correction":   true,
+	"scoring_error":     true,
+	"fair_play":         true,
+	"manual_adjustment": true,
+}
+
+type SettlementCorrectionService interface {
+	ProposeCorrection(contestID uuid.UUID, req *models.CreateSettlementCorrectionRequest) (*models.SettlementCorrection, error)
+	GetCorrection(id uuid.UUID) (*models.SettlementCorrection, error)
+	GetContestCorrections(contestID uuid.UUID) ([]models.SettlementCorrection, error)
+	ApproveCorrection(id uuid.UUID) (*models.SettlementCorrection, error)
+	RejectCorrection(id uuid.UUID, reason string) (*models.SettlementCorrection, error)
+}
+
+type settlementCorrectionService struct {
+	db                  *gorm.DB
+	scoringService      ScoringService
+	leaderboardService  LeaderboardService
+	notificationService NotificationService
+	ledgerService       LedgerService
+}
+
+func NewSettlementCorrectionService(db *gorm.DB, scoringService ScoringService, leaderboardService LeaderboardService, notificationService NotificationService, ledgerService LedgerService) SettlementCorrectionService {
+	return &settlementCorrectionService{
+		db:                  db,
+		scoringService:      scoringService,
+		leaderboardService:  leaderboardService,
+		notificationService: notificationService,
+		ledgerService:       ledgerService,
+	}
+}
+
+// ProposeCorrection re-runs scoring for a contest that has already paid out
+// and records, per user, how their rank and prize would change. Scores are
+// computed in memory; nothing is written to the standings or paid until an
+// admin approves the correction.
+func (s *settlementCorrectionService) ProposeCorrection(contestID uuid.UUID, req *models.CreateSettlementCorrectionRequest) (*models.SettlementCorrection, error) {
+	if !correctionReasonCodes[req.ReasonCode] {
+		return nil, fmt.Errorf("unknown reason code: %s", req.ReasonCode)
+	}
+
+	correction := &models.SettlementCorrection{
+		ContestID:  contestID,
+		Status:     CorrectionStatusPending,
+		ReasonCode: req.ReasonCode,
+		Reason:     req.Reason,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Locking the contest serialises proposals, so only one can be pending
+		var contest models.Contest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&contest, "id = ?", contestID).Error; err != nil {
+			return fmt.Errorf("failed to get contest: %w", err)
+		}
+		if !contest.PrizesDistributed {
+			return fmt.Errorf("contest %s has not been settled", contestID)
+		}
+
+		var pending int64
+		err := tx.Model(&models.SettlementCorrection{}).
+			Where("contest_id = ? AND status = ?", contestID, CorrectionStatusPending).
+			Count(&pending).Error
+		if err != nil {
+			return fmt.Errorf("failed to check pending corrections: %w", err)
+		}
+		if pending > 0 {
+			return fmt.Errorf("contest %s already has a pending correction", contestID)
+		}
+
+		// Rescore in memory; the totals are only written if the correction is approved
+		totals, err := s.scoringService.ContestTeamTotals(contestID)
+		if err != nil {
+			return fmt.Errorf("failed to rescore contest: %w", err)
+		}
+		totalsJSON, err := json.Marshal(totals)
+		if err != nil {
+			return fmt.Errorf("failed to serialize team totals: %w", err)
+		}
+
+		var teams []models.FantasyTeam
+		if err := tx.Where("contest_id = ?", contestID).Order("created_at ASC").Find(&teams).Error; err != nil {
+			return fmt.Errorf("failed to get fantasy teams: %w", err)
+		}
+		for i := range teams {
+			teams[i].TotalPoints = totals[teams[i].ID]
+		}
+		sort.SliceStable(teams, func(i, j int) bool { return teams[i].TotalPoints > teams[j].TotalPoints })
+
+		// New prizes and best ranks per user from the corrected standings
+		prizes, err := contestPrizesByRank(&contest, len(teams))
+		if err != nil {
+			return err
+		}
+		newPrizes := make(map[uuid.UUID]float64)
+		newRanks := make(map[uuid.UUID]int)
+		oldRanks := make(map[uuid.UUID]*int)
+		ranks, _ := competitionRanks(len(teams), func(i int) float64 { return teams[i].TotalPoints })
+		teamPrizes := splitTiedPrizes(prizes, ranks)
+		for i, team := range teams {
+			rank := ranks[i]
+			newPrizes[team.UserID] += teamPrizes[i]
+			if best, ok := newRanks[team.UserID]; !ok || rank < best {
+				newRanks[team.UserID] = rank
+			}
+			if team.Rank != nil && (oldRanks[team.UserID] == nil || *team.Rank < *oldRanks[team.UserID]) {
+				oldRank := *team.Rank
+				oldRanks[team.UserID] = &oldRank
+			}
+		}
+
+		oldPrizes, err := s.paidPrizesByUser(contestID)
+		if err != nil {
+			return err
+		}
+
+		correction.TeamTotals = string(totalsJSON)
+
+		for userID, newRank := range newRanks {
+			oldPrize := roundCurrency(oldPrizes[userID])
+			newPrize := roundCurrency(newPrizes[userID])
+			delta := roundCurrency(newPrize - oldPrize)
+			rankChanged := oldRanks[userID] == nil || *oldRanks[userID] != newRank
+			if delta == 0 && !rankChanged {
+				continue
+			}
+
+			correction.Entries = append(correction.Entries, models.SettlementCorrectionEntry{
+				UserID:   userID,
+				OldRank:  oldRanks[userID],
+				NewRank:  newRank,
+				OldPrize: oldPrize,
+				NewPrize: newPrize,
+				Delta:    delta,
+			})
+			if delta > 0 {
+				correction.TotalCredit += delta
+			} else {
+				correction.TotalClawback -= delta
+			}
+		}
+
+		// Users who were paid but no longer hold a team in the contest
+		for userID, oldPrize := range oldPrizes {
+			if _, ok := newRanks[userID]; ok || roundCurrency(oldPrize) == 0 {
+				continue
+			}
+			delta := -roundCurrency(oldPrize)
+			correction.Entries = append(correction.Entries, models.SettlementCorrectionEntry{
+				UserID:   userID,
+				OldPrize: roundCurrency(oldPrize),
+				Delta:    delta,
+			})
+			correction.TotalClawback -= delta
+		}
+
+		if err := tx.Create(correction).Error; err != nil {
+			return fmt.Errorf("failed to save correction: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("📝 Proposed settlement correction %s for contest %s: %d users, +₹%.2f / -₹%.2f",
+		correction.ID, contestID, len(correction.Entries), correction.TotalCredit, correction.TotalClawback)
+
+	return correction, nil
+}
+
+// paidPrizesByUser sums what each user has been paid for the contest so far,
+// including earlier corrections
+func (s *settlementCorrectionService) paidPrizesByUser(contestID uuid.UUID) (map[uuid.UUID]float64, error) {
+	var rows []struct {
+		UserID uuid.UUID
+		Amount float64
+	}
+	err := s.db.Model(&models.Transaction{}).
+		Select("user_id, SUM(CASE WHEN type = ? THEN -amount ELSE amount END) AS amount", TransactionTypePrizeClawback).
+		Where("related_entity_id = ? AND status = ? AND type IN ?", contestID, "completed",
+			[]string{TransactionTypeWinnings, TransactionTypePrizeCredit, TransactionTypePrizeClawback}).
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paid prizes: %w", err)
+	}
+
+	paid := make(map[uuid.UUID]float64, len(rows))
+	for _, row := range rows {
+		paid[row.UserID] = row.Amount
+	}
+	return paid, nil
+}
+
+func (s *settlementCorrectionService) GetCorrection(id uuid.UUID) (*models.SettlementCorrection, error) {
+	var correction models.SettlementCorrection
+	if err := s.db.Preload("Entries").First(&correction, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &correction, nil
+}
+
+func (s *settlementCorrectionService) GetContestCorrections(contestID uuid.UUID) ([]models.SettlementCorrection, error) {
+	var corrections []models.SettlementCorrection
+	err := s.db.Preload("Entries").
+		Where("contest_id = ?", contestID).
+		Order("created_at DESC").
+		Find(&corrections).Error
+	return corrections, err
+}
+
+// ApproveCorrection writes the rescored team totals and ranks, and posts a
+// credit or clawback transaction for every user whose prize changed, all in
+// one transaction. Approving the same correction twice is rejected.
+func (s *settlementCorrectionService) ApproveCorrection(id uuid.UUID) (*models.SettlementCorrection, error) {
+	var correction models.SettlementCorrection
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Entries").
+			First(&correction, "id = ?", id).Error
+		if err != nil {
+			return fmt.Errorf("correction not found")
+		}
+		if correction.Status != CorrectionStatusPending {
+			return fmt.Errorf("correction is already %s", correction.Status)
+		}
+
+		for i := range correction.Entries {
+			entry := &correction.Entries[i]
+			if entry.Delta == 0 {
+				continue
+			}
+
+			transaction := &models.Transaction{
+				UserID:          entry.UserID,
+				Amount:          math.Abs(entry.Delta),
+				Type:            TransactionTypePrizeCredit,
+				Status:          "completed",
+				RelatedEntityID: &correction.ContestID,
+				ReasonCode:      correction.ReasonCode,
+				Description:     fmt.Sprintf("Settlement correction %s", correction.ID),
+			}
+			if entry.Delta < 0 {
+				transaction.Type = TransactionTypePrizeClawback
+			}
+			SingleBucket(WalletBucketWinnings, transaction.Amount).ApplyTo(transaction)
+
+			if err := tx.Create(transaction).Error; err != nil {
+				return fmt.Errorf("failed to post adjustment: %w", err)
+			}
+
+			// Clawbacks may leave a negative balance that future winnings repay
+			postings := []LedgerPosting{
+				Debit(PlatformRakeAccount(), transaction.Amount),
+				Credit(UserWinningsAccount(entry.UserID), transaction.Amount),
+			}
+			if entry.Delta < 0 {
+				postings = []LedgerPosting{
+					Debit(UserWinningsAccount(entry.UserID), transaction.Amount),
+					Credit(PlatformRakeAccount(), transaction.Amount),
+				}
+			}
+			_, err := s.ledgerService.Post(tx, &JournalEntryRequest{
+				Kind:           JournalKindPrizeCorrection,
+				ReferenceID:    &correction.ContestID,
+				TransactionID:  &transaction.ID,
+				IdempotencyKey: fmt.Sprintf("settlement_correction:%s", entry.ID),
+				Description:    transaction.Description,
+				Postings:       postings,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update wallet: %w", err)
+			}
+
+			entry.TransactionID = &transaction.ID
+			if err := tx.Model(entry).Update("transaction_id", transaction.ID).Error; err != nil {
+				return fmt.Errorf("failed to link adjustment: %w", err)
+			}
+		}
+
+		if err := s.saveTeamTotals(tx, &correction); err != nil {
+			return err
+		}
+		if err := s.saveContestRanks(tx, correction.ContestID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		correction.Status = CorrectionStatusApplied
+		correction.AppliedAt = &now
+		return tx.Model(&correction).Updates(map[string]interface{}{
+			"status":     correction.Status,
+			"applied_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.leaderboardService.RefreshContestLeaderboard(correction.ContestID); err != nil {
+		log.Printf("Error refreshing leaderboard for contest %s: %v", correction.ContestID, err)
+	}
+
+	s.notifyUsers(&correction)
+
+	log.Printf("✅ Applied settlement correction %s for contest %s", correction.ID, correction.ContestID)
+
+	return &correction, nil
+}
+
+// saveTeamTotals writes the total points the correction rescored each team to
+func (s *settlementCorrectionService) saveTeamTotals(tx *gorm.DB, correction *models.SettlementCorrection) error {
+	if correction.TeamTotals == "" {
+		return nil
+	}
+
+	var totals map[uuid.UUID]float64
+	if err := json.Unmarshal([]byte(correction.TeamTotals), &totals); err != nil {
+		return fmt.Errorf("failed to parse team totals: %w", err)
+	}
+
+	for teamID, totalPoints := range totals {
+		err := tx.Model(&models.FantasyTeam{}).
+			Where("id = ? AND contest_id = ?", teamID, correction.ContestID).
+			Updates(map[string]interface{}{"total_points": totalPoints, "updated_at": time.Now()}).Error
+		if err != nil {
+			return fmt.Errorf("failed to save team totals: %w", err)
+		}
+	}
+	return nil
+}
+
+// saveContestRanks stores the corrected final rank and tie flag of every team
+func (s *settlementCorrectionService) saveContestRanks(tx *gorm.DB, contestID uuid.UUID) error {
+	var teams []models.FantasyTeam
+	err := tx.Where("contest_id = ?", contestID).
+		Order("total_points DESC, created_at ASC").
+		Find(&teams).Error
+	if err != nil {
+		return fmt.Errorf("failed to get fantasy teams: %w", err)
+	}
+
+	ranks, tied := competitionRanks(len(teams), func(i int) float64 { return teams[i].TotalPoints })
+	for i, team := range teams {
+		err := tx.Model(&models.FantasyTeam{}).Where("id = ?", team.ID).
+			Updates(map[string]interface{}{"rank": ranks[i], "is_tied": tied[i]}).Error
+		if err != nil {
+			return fmt.Errorf("failed to save ranks: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *settlementCorrectionService) RejectCorrection(id uuid.UUID, reason string) (*models.SettlementCorrection, error) {
+	var correction models.SettlementCorrection
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Entries").
+			First(&correction, "id = ?", id).Error
+		if err != nil {
+			return fmt.Errorf("correction not found")
+		}
+		if correction.Status != CorrectionStatusPending {
+			return fmt.Errorf("correction is already %s", correction.Status)
+		}
+
+		correction.Status = CorrectionStatusRejected
+		correction.RejectedReason = reason
+		return tx.Model(&correction).Updates(map[string]interface{}{
+			"status":          correction.Status,
+			"rejected_reason": reason,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &correction, nil
+}
+
+func (s *settlementCorrectionService) notifyUsers(correction *models.SettlementCorrection) {
+	for _, entry := range correction.Entries {
+		if entry.Delta == 0 {
+			continue
+		}
+
+		title := "Contest winnings increased"
+		message := fmt.Sprintf("Results were corrected and ₹%.2f has been credited to your wallet.", entry.Delta)
+		if entry.Delta < 0 {
+			title = "Contest winnings reduced"
+			message = fmt.Sprintf("Results were corrected and ₹%.2f has been deducted from your wallet.", -entry.Delta)
+		}
+
+		data := map[string]interface{}{
+			"contest_id":    correction.ContestID,
+			"correction_id": correction.ID,
+			"reason_code":   correction.ReasonCode,
+			"old_rank":      entry.OldRank,
+			"new_rank":      entry.NewRank,
+			"delta":         entry.Delta,
+		}
+
+		if err := s.notificationService.Notify(entry.UserID, "prize_adjustment", title, message, data); err != nil {
+			log.Printf("Error notifying user %s of prize adjustment: %v", entry.UserID, err)
+		}
+	}
+}
+
+// roundCurrency rounds an amount to whole paise
+func roundCurrency(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
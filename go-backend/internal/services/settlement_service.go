@@ -0,0 +1,365 @@
+package services
+
+import (
+	"errors"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Settlement and payout statuses
+const (
+	SettlementStatusPending   = "pending"
+	SettlementStatusCompleted = "completed"
+
+	PayoutStatusPending = "pending"
+	PayoutStatusPaid    = "paid"
+)
+
+// Statuses a contest and its match must have reached before it is settled
+const (
+	ContestStatusLocked    = "locked"
+	ContestStatusCompleted = "completed"
+	MatchStatusCompleted   = "completed"
+)
+
+var ErrContestNotSettleable = errors.New("contest is not ready to be settled")
+
+type SettlementService interface {
+	SettleContest(contestID uuid.UUID) (*models.ContestSettlement, error)
+	GetContestSettlement(contestID uuid.UUID) (*models.ContestSettlement, error)
+}
+
+type settlementService struct {
+	db            *gorm.DB
+	ledgerService LedgerService
+}
+
+func NewSettlementService(db *gorm.DB, ledgerService LedgerService) SettlementService {
+	return &settlementService{
+		db:            db,
+		ledgerService: ledgerService,
+	}
+}
+
+// settlementKey identifies the one settlement a contest may have
+func settlementKey(contestID uuid.UUID) string {
+	return fmt.Sprintf("contest:%s", contestID)
+}
+
+// payoutIdempotencyKey identifies the wallet credit for one payout row
+func payoutIdempotencyKey(payoutID uuid.UUID) string {
+	return fmt.Sprintf("settlement_payout:%s", payoutID)
+}
+
+// SettleContest pays out a contest. The first run fixes the final standings
+// and payout rows; crediting then happens in a single transaction. Running it
+// again, from the scheduler or an admin, completes a settlement that did not
+// finish and is a no-op for one that did.
+func (s *settlementService) SettleContest(contestID uuid.UUID) (*models.ContestSettlement, error) {
+	settlement, err := s.planSettlement(contestID)
+	if err != nil {
+		return nil, err
+	}
+	if settlement.Status == SettlementStatusCompleted {
+		return settlement, nil
+	}
+
+	return s.creditSettlement(settlement.ID)
+}
+
+func (s *settlementService) GetContestSettlement(contestID uuid.UUID) (*models.ContestSettlement, error) {
+	var settlement models.ContestSettlement
+	err := s.db.Preload("Payouts", func(db *gorm.DB) *gorm.DB {
+		return db.Order("rank ASC")
+	}).First(&settlement, "contest_id = ?", contestID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &settlement, nil
+}
+
+// planSettlement returns the contest's settlement, creating it with one
+// payout row per winning team on the first call
+func (s *settlementService) planSettlement(contestID uuid.UUID) (*models.ContestSettlement, error) {
+	var settlement models.ContestSettlement
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Serialises the scheduler and manual settlement of the same contest
+		var contest models.Contest
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&contest, "id = ?", contestID).Error
+		if err != nil {
+			return fmt.Errorf("failed to get contest: %w", err)
+		}
+
+		err = tx.Preload("Payouts").First(&settlement, "contest_id = ?", contestID).Error
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get settlement: %w", err)
+		}
+		if contest.PrizesDistributed {
+			return fmt.Errorf("contest %s was paid out before settlement records were kept", contestID)
+		}
+		if strings.EqualFold(contest.Status, ContestStatusCancelled) {
+			return fmt.Errorf("contest %s was cancelled and its entry fees refunded", contestID)
+		}
+
+		// Standings are frozen here, so only final scores may be settled
+		if !strings.EqualFold(contest.Status, ContestStatusLocked) && !strings.EqualFold(contest.Status, ContestStatusCompleted) {
+			return fmt.Errorf("%w: contest %s is %s", ErrContestNotSettleable, contestID, contest.Status)
+		}
+		var match models.Match
+		if err := tx.First(&match, "id = ?", contest.MatchID).Error; err != nil {
+			return fmt.Errorf("failed to get match: %w", err)
+		}
+		if !strings.EqualFold(match.Status, MatchStatusCompleted) {
+			return fmt.Errorf("%w: match %s is %s", ErrContestNotSettleable, match.ID, match.Status)
+		}
+
+		var teams []models.FantasyTeam
+		err = tx.Where("contest_id = ?", contestID).
+			Order("total_points DESC, created_at ASC").
+			Find(&teams).Error
+		if err != nil {
+			return fmt.Errorf("failed to get fantasy teams: %w", err)
+		}
+
+		engine, err := NewPrizeEngine(contest.PrizePool)
+		if err != nil {
+			return fmt.Errorf("contest %s: %w", contestID, err)
+		}
+
+		settlement = models.ContestSettlement{
+			ContestID:     contestID,
+			SettlementKey: settlementKey(contestID),
+			Status:        SettlementStatusPending,
+			Participants:  len(teams),
+		}
+		if len(teams) > 0 {
+			settlement.PrizePool = engine.Pool(contest.EntryFee, len(teams))
+		}
+
+		ranks, tied := competitionRanks(len(teams), func(i int) float64 { return teams[i].TotalPoints })
+		prizes := splitTiedPrizes(engine.Distribute(contest.EntryFee, len(teams)), ranks)
+		for i, team := range teams {
+			err := tx.Model(&models.FantasyTeam{}).Where("id = ?", team.ID).
+				Updates(map[string]interface{}{"rank": ranks[i], "is_tied": tied[i]}).Error
+			if err != nil {
+				return fmt.Errorf("failed to save final ranks: %w", err)
+			}
+
+			if prizes[i] <= 0 {
+				continue
+			}
+			settlement.Payouts = append(settlement.Payouts, models.SettlementPayout{
+				FantasyTeamID: team.ID,
+				UserID:        team.UserID,
+				Rank:          ranks[i],
+				IsTied:        tied[i],
+				Amount:        prizes[i],
+				Status:        PayoutStatusPending,
+			})
+		}
+
+		if err := tx.Create(&settlement).Error; err != nil {
+			return fmt.Errorf("failed to create settlement: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &settlement, nil
+}
+
+// creditSettlement pays every unpaid payout of a settlement and marks the
+// contest as distributed, all or nothing
+func (s *settlementService) creditSettlement(settlementID uuid.UUID) (*models.ContestSettlement, error) {
+	var settlement models.ContestSettlement
+	paidNow := 0
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Payouts").
+			First(&settlement, "id = ?", settlementID).Error
+		if err != nil {
+			return fmt.Errorf("failed to get settlement: %w", err)
+		}
+		if settlement.Status == SettlementStatusCompleted {
+			return nil
+		}
+
+		legacy, err := s.unclaimedWinnings(tx, settlement.ContestID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i := range settlement.Payouts {
+			payout := &settlement.Payouts[i]
+			if payout.Status == PayoutStatusPaid {
+				continue
+			}
+
+			// Adopt a credit made for this prize by an earlier, interrupted
+			// distribution instead of paying it twice
+			if transactionID := takeMatchingWinnings(legacy, payout.UserID, payout.Amount); transactionID != nil {
+				payout.TransactionID = transactionID
+			} else {
+				key := payoutIdempotencyKey(payout.ID)
+				transaction := &models.Transaction{
+					UserID:          payout.UserID,
+					Amount:          payout.Amount,
+					Type:            TransactionTypeWinnings,
+					Status:          "completed",
+					RelatedEntityID: &settlement.ContestID,
+					Description:     fmt.Sprintf("Contest prize, rank %d", payout.Rank),
+					IdempotencyKey:  &key,
+				}
+				SingleBucket(WalletBucketWinnings, payout.Amount).ApplyTo(transaction)
+				if err := tx.Create(transaction).Error; err != nil {
+					return fmt.Errorf("failed to create prize transaction: %w", err)
+				}
+
+				_, err := s.ledgerService.Post(tx, &JournalEntryRequest{
+					Kind:           JournalKindPrize,
+					ReferenceID:    &settlement.ContestID,
+					TransactionID:  &transaction.ID,
+					IdempotencyKey: key,
+					Description:    transaction.Description,
+					Postings: []LedgerPosting{
+						Debit(PrizeEscrowAccount(), payout.Amount),
+						Credit(UserWinningsAccount(payout.UserID), payout.Amount),
+					},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to credit wallet: %w", err)
+				}
+
+				payout.TransactionID = &transaction.ID
+				paidNow++
+			}
+
+			payout.Status = PayoutStatusPaid
+			payout.PaidAt = &now
+			err := tx.Model(payout).Updates(map[string]interface{}{
+				"status":         payout.Status,
+				"transaction_id": payout.TransactionID,
+				"paid_at":        now,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("failed to mark payout paid: %w", err)
+			}
+			settlement.TotalPaid += payout.Amount
+		}
+
+		if err := s.closeEscrow(tx, settlement.ContestID); err != nil {
+			return err
+		}
+
+		settlement.TotalPaid = roundCurrency(settlement.TotalPaid)
+		settlement.Status = SettlementStatusCompleted
+		settlement.CompletedAt = &now
+		err = tx.Model(&settlement).Updates(map[string]interface{}{
+			"status":       settlement.Status,
+			"total_paid":   settlement.TotalPaid,
+			"completed_at": now,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to complete settlement: %w", err)
+		}
+
+		return tx.Model(&models.Contest{}).Where("id = ?", settlement.ContestID).
+			Updates(map[string]interface{}{"prizes_distributed": true, "updated_at": now}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("💰 Settled contest %s: %d payouts (%d credited now), ₹%.2f paid",
+		settlement.ContestID, len(settlement.Payouts), paidNow, settlement.TotalPaid)
+
+	return &settlement, nil
+}
+
+// closeEscrow moves what is left of a contest's entry fees after prizes to
+// platform rake. A shortfall, from a guaranteed pool or entries taken before
+// the ledger, is funded by the platform.
+func (s *settlementService) closeEscrow(tx *gorm.DB, contestID uuid.UUID) error {
+	remaining, err := s.ledgerService.EscrowBalance(tx, contestID)
+	if err != nil {
+		return err
+	}
+	if remaining == 0 {
+		return nil
+	}
+
+	postings := []LedgerPosting{
+		Debit(PrizeEscrowAccount(), remaining),
+		Credit(PlatformRakeAccount(), remaining),
+	}
+	if remaining < 0 {
+		postings = []LedgerPosting{
+			Debit(PlatformRakeAccount(), -remaining),
+			Credit(PrizeEscrowAccount(), -remaining),
+		}
+	}
+
+	_, err = s.ledgerService.Post(tx, &JournalEntryRequest{
+		Kind:           JournalKindPrize,
+		ReferenceID:    &contestID,
+		IdempotencyKey: fmt.Sprintf("settlement_rake:%s", contestID),
+		Description:    "Contest rake",
+		Postings:       postings,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to close contest escrow: %w", err)
+	}
+	return nil
+}
+
+type winningsCredit struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Amount float64
+}
+
+// unclaimedWinnings returns winnings already credited for a contest that no
+// payout row owns, grouped by user
+func (s *settlementService) unclaimedWinnings(tx *gorm.DB, contestID uuid.UUID) (map[uuid.UUID][]winningsCredit, error) {
+	var credits []winningsCredit
+	err := tx.Model(&models.Transaction{}).
+		Select("id, user_id, amount").
+		Where("related_entity_id = ? AND type = ? AND status = ?", contestID, TransactionTypeWinnings, "completed").
+		Where("id NOT IN (?)", tx.Model(&models.SettlementPayout{}).Select("transaction_id").Where("transaction_id IS NOT NULL")).
+		Scan(&credits).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing winnings: %w", err)
+	}
+
+	byUser := make(map[uuid.UUID][]winningsCredit)
+	for _, credit := range credits {
+		byUser[credit.UserID] = append(byUser[credit.UserID], credit)
+	}
+	return byUser, nil
+}
+
+// takeMatchingWinnings removes and returns an unclaimed credit to the user for
+// the given amount, if there is one
+func takeMatchingWinnings(credits map[uuid.UUID][]winningsCredit, userID uuid.UUID, amount float64) *uuid.UUID {
+	for i, credit := range credits[userID] {
+		if roundCurrency(credit.Amount) == roundCurrency(amount) {
+			credits[userID] = append(credits[userID][:i], credits[userID][i+1:]...)
+			id := credit.ID
+			return &id
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Columns accepted in a bulk stats CSV sheet. Only player_id is required.
+var statsImportColumns = []string{
+	"player_id",
+	"kills",
+	"revives",
+	"knockouts",
+	"survival_time_minutes",
+	"is_mvp",
+	"team_kill_penalty",
+}
+
+// statsImportJSONRow mirrors a CSV row for JSON sheets
+type statsImportJSONRow struct {
+	PlayerID            string `json:"player_id"`
+	Kills               int    `json:"kills"`
+	Revives             int    `json:"revives"`
+	Knockouts           int    `json:"knockouts"`
+	SurvivalTimeMinutes int    `json:"survival_time_minutes"`
+	IsMVP               bool   `json:"is_mvp"`
+	TeamKillPenalty     int    `json:"team_kill_penalty"`
+}
+
+// ParseStatsImportCSV reads a stats sheet with a header row. Rows that
+// cannot be parsed are returned with ParseError set so they can be reported
+// alongside validation errors.
+func ParseStatsImportCSV(r io.Reader) ([]models.PlayerStatsImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["player_id"]; !ok {
+		return nil, fmt.Errorf("CSV header must include player_id")
+	}
+	for name := range columns {
+		if !containsString(statsImportColumns, name) {
+			return nil, fmt.Errorf("unknown CSV column: %s", name)
+		}
+	}
+
+	var rows []models.PlayerStatsImportRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		row := models.PlayerStatsImportRow{Row: line - 1}
+		if err != nil {
+			row.ParseError = err.Error()
+			rows = append(rows, row)
+			continue
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row.PlayerID, err = uuid.Parse(field("player_id"))
+		if err != nil {
+			row.ParseError = "invalid player_id"
+			rows = append(rows, row)
+			continue
+		}
+
+		ints := []struct {
+			name   string
+			target *int
+		}{
+			{"kills", &row.Stats.Kills},
+			{"revives", &row.Stats.Revives},
+			{"knockouts", &row.Stats.Knockouts},
+			{"survival_time_minutes", &row.Stats.SurvivalTimeMinutes},
+			{"team_kill_penalty", &row.Stats.TeamKillPenalty},
+		}
+		for _, column := range ints {
+			value := field(column.name)
+			if value == "" {
+				continue
+			}
+			if *column.target, err = strconv.Atoi(value); err != nil {
+				row.ParseError = fmt.Sprintf("invalid %s: %q", column.name, value)
+				break
+			}
+		}
+
+		if value := field("is_mvp"); value != "" && row.ParseError == "" {
+			if row.Stats.IsMVP, err = strconv.ParseBool(value); err != nil {
+				row.ParseError = fmt.Sprintf("invalid is_mvp: %q", value)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ParseStatsImportJSON reads a stats sheet given as a JSON array of rows
+func ParseStatsImportJSON(r io.Reader) ([]models.PlayerStatsImportRow, error) {
+	var records []statsImportJSONRow
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON sheet: %w", err)
+	}
+
+	rows := make([]models.PlayerStatsImportRow, 0, len(records))
+	for i, record := range records {
+		row := models.PlayerStatsImportRow{
+			Row: i + 1,
+			Stats: models.UpdateStatsRequest{
+				Kills:               record.Kills,
+				Revives:             record.Revives,
+				Knockouts:           record.Knockouts,
+				SurvivalTimeMinutes: record.SurvivalTimeMinutes,
+				IsMVP:               record.IsMVP,
+				TeamKillPenalty:     record.TeamKillPenalty,
+			},
+		}
+
+		playerID, err := uuid.Parse(record.PlayerID)
+		if err != nil {
+			row.ParseError = "invalid player_id"
+		}
+		row.PlayerID = playerID
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ImportPlayerStats validates a whole match's stats sheet and, unless this
+// is a dry run, writes every row in a single transaction followed by one
+// leaderboard update. Nothing is written if any row is invalid.
+func (s *scoringService) ImportPlayerStats(matchID uuid.UUID, rows []models.PlayerStatsImportRow, dryRun bool) (*models.StatsImportResult, error) {
+	var match models.Match
+	if err := s.db.Preload("Tournament").First(&match, "id = ?", matchID).Error; err != nil {
+		return nil, fmt.Errorf("match not found")
+	}
+
+	result := &models.StatsImportResult{
+		MatchID:   matchID,
+		DryRun:    dryRun,
+		TotalRows: len(rows),
+		Errors:    []models.StatsImportRowError{},
+	}
+
+	playerIDs := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		if row.ParseError == "" {
+			playerIDs = append(playerIDs, row.PlayerID)
+		}
+	}
+
+	var players []models.Player
+	if len(playerIDs) > 0 {
+		if err := s.db.Where("id IN ?", playerIDs).Find(&players).Error; err != nil {
+			return nil, fmt.Errorf("failed to load players: %w", err)
+		}
+	}
+	playersByID := make(map[uuid.UUID]models.Player, len(players))
+	for _, player := range players {
+		playersByID[player.ID] = player
+	}
+
+	seen := make(map[uuid.UUID]int)
+	for _, row := range rows {
+		if message := validateStatsImportRow(row, playersByID, match.Tournament.GameID, seen); message != "" {
+			rowError := models.StatsImportRowError{Row: row.Row, Error: message}
+			if row.PlayerID != uuid.Nil {
+				rowError.PlayerID = row.PlayerID.String()
+			}
+			result.Errors = append(result.Errors, rowError)
+			continue
+		}
+		seen[row.PlayerID] = row.Row
+		result.ValidRows++
+	}
+
+	if dryRun || len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	var deltas []teamPointsDelta
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			target := models.PlayerMatchStats{
+				MatchID:             matchID,
+				PlayerID:            row.PlayerID,
+				Kills:               row.Stats.Kills,
+				Revives:             row.Stats.Revives,
+				Knockouts:           row.Stats.Knockouts,
+				SurvivalTimeMinutes: row.Stats.SurvivalTimeMinutes,
+				IsMVP:               row.Stats.IsMVP,
+				TeamKillPenalty:     row.Stats.TeamKillPenalty,
+			}
+			_, rowDeltas, err := s.setPlayerStats(tx, &target)
+			if err != nil {
+				return fmt.Errorf("row %d: %w", row.Row, err)
+			}
+			deltas = append(deltas, rowDeltas...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Imported = len(rows)
+	s.publishTeamPointsDeltas(matchID, deltas)
+
+	log.Printf("✅ Imported stats for %d players in match %s", result.Imported, matchID)
+
+	return result, nil
+}
+
+// validateStatsImportRow returns why a row cannot be imported, or an empty
+// string if it is valid
+func validateStatsImportRow(row models.PlayerStatsImportRow, players map[uuid.UUID]models.Player, gameID uuid.UUID, seen map[uuid.UUID]int) string {
+	if row.ParseError != "" {
+		return row.ParseError
+	}
+
+	player, exists := players[row.PlayerID]
+	if !exists {
+		return "player not found"
+	}
+	if gameID != uuid.Nil && player.GameID != gameID {
+		return "player does not belong to this match's game"
+	}
+	if firstRow, duplicate := seen[row.PlayerID]; duplicate {
+		return fmt.Sprintf("duplicate player, already on row %d", firstRow)
+	}
+
+	stats := row.Stats
+	if stats.Kills < 0 || stats.Revives < 0 || stats.Knockouts < 0 || stats.SurvivalTimeMinutes < 0 || stats.TeamKillPenalty < 0 {
+		return "stats cannot be negative"
+	}
+
+	return ""
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Rules used when a game sets no team composition of its own
+const (
+	defaultTeamSize  = 5
+	defaultCreditCap = 100.0
+)
+
+// Ways a lineup can break its game's team rules
+const (
+	TeamViolationTeamSize        = "team_size"
+	TeamViolationDuplicatePlayer = "duplicate_player"
+	TeamViolationCaptain         = "captain_not_selected"
+	TeamViolationViceCaptain     = "vice_captain_not_selected"
+	TeamViolationSameCaptain     = "captain_is_vice_captain"
+	TeamViolationUnknownPlayer   = "player_not_found"
+	TeamViolationWrongGame       = "player_wrong_game"
+	TeamViolationNotInMatch      = "player_not_in_match"
+	TeamViolationUnknownRole     = "unknown_role"
+	TeamViolationRoleMin         = "role_min"
+	TeamViolationRoleMax         = "role_max"
+	TeamViolationSquadMax        = "esports_team_max"
+	TeamViolationCreditCap       = "credit_cap"
+)
+
+var ErrInvalidTeamComposition = errors.New("invalid team composition")
+
+// TeamViolation - One rule a lineup breaks, tied to the request field at fault
+type TeamViolation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// TeamValidationError lists every rule a lineup breaks, so the whole team can
+// be fixed in one go
+type TeamValidationError struct {
+	Violations []TeamViolation `json:"violations"`
+}
+
+func (e *TeamValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return "invalid team: " + strings.Join(messages, "; ")
+}
+
+func (e *TeamValidationError) add(field, code, format string, args ...interface{}) {
+	e.Violations = append(e.Violations, TeamViolation{
+		Field:   field,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// teamRules - A game's team composition with defaults filled in
+type teamRules struct {
+	models.TeamComposition
+	MinPlayers int
+	MaxPlayers int
+	Roles      []string // Roles the game has; empty allows any
+}
+
+// gameTeamRules reads a game's team composition. Without a team size of its
+// own, a team may have the game's min to max players per team; a nil game
+// falls back to the old five players and 100 credits.
+func gameTeamRules(game *models.Game) (*teamRules, error) {
+	rules := &teamRules{}
+	if game == nil {
+		rules.TeamSize = defaultTeamSize
+		rules.CreditCap = defaultCreditCap
+		rules.MinPlayers = defaultTeamSize
+		rules.MaxPlayers = defaultTeamSize
+		return rules, nil
+	}
+
+	if game.TeamComposition != "" {
+		if err := json.Unmarshal([]byte(game.TeamComposition), &rules.TeamComposition); err != nil {
+			return nil, fmt.Errorf("failed to parse team composition for %s: %w", game.Name, err)
+		}
+	}
+	if game.PlayerRoles != "" {
+		if err := json.Unmarshal([]byte(game.PlayerRoles), &rules.Roles); err != nil {
+			return nil, fmt.Errorf("failed to parse player roles for %s: %w", game.Name, err)
+		}
+	}
+
+	if rules.CreditCap <= 0 {
+		rules.CreditCap = defaultCreditCap
+	}
+	switch {
+	case rules.TeamSize > 0:
+		rules.MinPlayers = rules.TeamSize
+		rules.MaxPlayers = rules.TeamSize
+	case game.MinPlayersPerTeam > 0 || game.MaxPlayersPerTeam > 0:
+		rules.MinPlayers = game.MinPlayersPerTeam
+		rules.MaxPlayers = game.MaxPlayersPerTeam
+	default:
+		rules.MinPlayers = defaultTeamSize
+		rules.MaxPlayers = defaultTeamSize
+	}
+	return rules, nil
+}
+
+// validateTeamComposition checks a game's rules can be met at all
+func validateTeamComposition(composition *models.TeamComposition, roles []string, minPlayers, maxPlayers int) error {
+	if composition.TeamSize < 0 || composition.CreditCap < 0 || composition.MaxPerESportsTeam < 0 {
+		return fmt.Errorf("%w: team size, credit cap and players per squad cannot be negative", ErrInvalidTeamComposition)
+	}
+	if composition.TeamSize > 0 {
+		minPlayers, maxPlayers = composition.TeamSize, composition.TeamSize
+	}
+
+	known := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		known[strings.ToLower(role)] = true
+	}
+
+	seen := make(map[string]bool)
+	required := 0
+	for _, limit := range composition.Roles {
+		role := strings.ToLower(limit.Role)
+		switch {
+		case role == "":
+			return fmt.Errorf("%w: every role limit needs a role", ErrInvalidTeamComposition)
+		case len(known) > 0 && !known[role]:
+			return fmt.Errorf("%w: %s is not one of the game's player roles", ErrInvalidTeamComposition, limit.Role)
+		case seen[role]:
+			return fmt.Errorf("%w: %s has more than one limit", ErrInvalidTeamComposition, limit.Role)
+		case limit.Min < 0 || limit.Max < 0:
+			return fmt.Errorf("%w: %s limits cannot be negative", ErrInvalidTeamComposition, limit.Role)
+		case limit.Max > 0 && limit.Min > limit.Max:
+			return fmt.Errorf("%w: %s needs at least %d but allows at most %d", ErrInvalidTeamComposition, limit.Role, limit.Min, limit.Max)
+		}
+		seen[role] = true
+		required += limit.Min
+	}
+	if maxPlayers > 0 && required > maxPlayers {
+		return fmt.Errorf("%w: role minimums need %d players but a team has at most %d", ErrInvalidTeamComposition, required, maxPlayers)
+	}
+	return nil
+}
+
+// checkLineup collects every way a lineup breaks the rules. players are the
+// lineup's players as found, game is nil when the match has no game, and
+// matchTeams is empty when the match has no squads recorded.
+func checkLineup(rules *teamRules, game *models.Game, matchTeams map[uuid.UUID]bool, playerIDs []uuid.UUID, captainID, viceCaptainID uuid.UUID, players []models.Player) *TeamValidationError {
+	violations := &TeamValidationError{}
+
+	switch {
+	case rules.MinPlayers == rules.MaxPlayers && len(playerIDs) != rules.MinPlayers:
+		violations.add("player_ids", TeamViolationTeamSize, "team must have exactly %d players", rules.MinPlayers)
+	case len(playerIDs) < rules.MinPlayers:
+		violations.add("player_ids", TeamViolationTeamSize, "team must have at least %d players", rules.MinPlayers)
+	case rules.MaxPlayers > 0 && len(playerIDs) > rules.MaxPlayers:
+		violations.add("player_ids", TeamViolationTeamSize, "team can have at most %d players", rules.MaxPlayers)
+	}
+
+	selected := make(map[uuid.UUID]bool, len(playerIDs))
+	for i, playerID := range playerIDs {
+		if selected[playerID] {
+			violations.add(fmt.Sprintf("player_ids[%d]", i), TeamViolationDuplicatePlayer, "player %s is selected more than once", playerID)
+		}
+		selected[playerID] = true
+	}
+
+	if !selected[captainID] {
+		violations.add("captain_id", TeamViolationCaptain, "captain must be one of the selected players")
+	}
+	if !selected[viceCaptainID] {
+		violations.add("vice_captain_id", TeamViolationViceCaptain, "vice captain must be one of the selected players")
+	}
+	if captainID == viceCaptainID {
+		violations.add("vice_captain_id", TeamViolationSameCaptain, "captain and vice captain must be different players")
+	}
+
+	found := make(map[uuid.UUID]*models.Player, len(players))
+	for i := range players {
+		found[players[i].ID] = &players[i]
+	}
+
+	known := make(map[string]bool, len(rules.Roles))
+	for _, role := range rules.Roles {
+		known[strings.ToLower(role)] = true
+	}
+
+	roleCounts := make(map[string]int)
+	squadCounts := make(map[uuid.UUID]int)
+	totalCredits := 0.0
+	counted := make(map[uuid.UUID]bool, len(playerIDs))
+	for i, playerID := range playerIDs {
+		field := fmt.Sprintf("player_ids[%d]", i)
+		player, ok := found[playerID]
+		if !ok {
+			violations.add(field, TeamViolationUnknownPlayer, "player %s not found", playerID)
+			continue
+		}
+		if counted[playerID] {
+			continue
+		}
+		counted[playerID] = true
+
+		if game != nil && player.GameID != game.ID {
+			violations.add(field, TeamViolationWrongGame, "%s does not play %s", player.Name, game.DisplayName)
+		}
+		if len(matchTeams) > 0 && !matchTeams[player.ESportsTeamID] {
+			violations.add(field, TeamViolationNotInMatch, "%s's team is not playing in this match", player.Name)
+		}
+		role := strings.ToLower(player.Role)
+		if len(known) > 0 && !known[role] {
+			violations.add(field, TeamViolationUnknownRole, "%s has role %q, which the game does not have", player.Name, player.Role)
+		}
+
+		roleCounts[role]++
+		squadCounts[player.ESportsTeamID]++
+		totalCredits += player.CreditValue
+	}
+
+	for _, limit := range rules.TeamComposition.Roles {
+		count := roleCounts[strings.ToLower(limit.Role)]
+		if count < limit.Min {
+			violations.add("player_ids", TeamViolationRoleMin, "team needs at least %d %s (has %d)", limit.Min, limit.Role, count)
+		}
+		if limit.Max > 0 && count > limit.Max {
+			violations.add("player_ids", TeamViolationRoleMax, "team can have at most %d %s (has %d)", limit.Max, limit.Role, count)
+		}
+	}
+
+	if rules.MaxPerESportsTeam > 0 {
+		for _, player := range players {
+			count := squadCounts[player.ESportsTeamID]
+			if count > rules.MaxPerESportsTeam {
+				violations.add("player_ids", TeamViolationSquadMax, "team can have at most %d players from %s (has %d)",
+					rules.MaxPerESportsTeam, player.ESportsTeam.Name, count)
+				delete(squadCounts, player.ESportsTeamID) // Report each squad once
+			}
+		}
+	}
+
+	if totalCredits > rules.CreditCap {
+		violations.add("player_ids", TeamViolationCreditCap, "team exceeds credit limit (%.1f/%.1f)", totalCredits, rules.CreditCap)
+	}
+
+	if len(violations.Violations) == 0 {
+		return nil
+	}
+	return violations
+}
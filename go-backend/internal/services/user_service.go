@@ -15,6 +15,7 @@ type UserService interface {
 	UpdateUserProfile(userID uuid.UUID, name, username, profileImage string) error
 	UpdateProfileImage(userID uuid.UUID, profileImage string) error
 	GetWalletBalance(userID uuid.UUID) (float64, error)
+	GetWallet(userID uuid.UUID) (*models.WalletBalanceResponse, error)
 }
 
 type userService struct {
@@ -59,6 +60,26 @@ func (s *userService) GetWalletBalance(userID uuid.UUID) (float64, error) {
 	return user.WalletBalance, nil
 }
 
+func (s *userService) GetWallet(userID uuid.UUID) (*models.WalletBalanceResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	withdrawable := user.WinningsBalance
+	if withdrawable < 0 {
+		withdrawable = 0
+	}
+
+	return &models.WalletBalanceResponse{
+		Balance:      user.WalletBalance,
+		Deposit:      user.DepositBalance,
+		Winnings:     user.WinningsBalance,
+		Bonus:        user.BonusBalance,
+		Withdrawable: withdrawable,
+	}, nil
+}
+
 func (s *userService) UpdateUserProfile(userID uuid.UUID, name, username, profileImage string) error {
 	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"esports-fantasy-backend/config"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Wallet buckets. Deposits and bonus credit can only be played; winnings are
+// the only withdrawable money.
+const (
+	WalletBucketDeposit  = "deposit"
+	WalletBucketWinnings = "winnings"
+	WalletBucketBonus    = "bonus"
+	WalletBucketMixed    = "mixed"
+)
+
+// WalletBuckets holds an amount per wallet bucket
+type WalletBuckets struct {
+	Deposit  float64 `json:"deposit"`
+	Winnings float64 `json:"winnings"`
+	Bonus    float64 `json:"bonus"`
+}
+
+func (b WalletBuckets) Total() float64 {
+	return roundCurrency(b.Deposit + b.Winnings + b.Bonus)
+}
+
+func (b WalletBuckets) get(bucket string) float64 {
+	switch bucket {
+	case WalletBucketDeposit:
+		return b.Deposit
+	case WalletBucketWinnings:
+		return b.Winnings
+	case WalletBucketBonus:
+		return b.Bonus
+	}
+	return 0
+}
+
+func (b *WalletBuckets) add(bucket string, amount float64) {
+	switch bucket {
+	case WalletBucketDeposit:
+		b.Deposit = roundCurrency(b.Deposit + amount)
+	case WalletBucketWinnings:
+		b.Winnings = roundCurrency(b.Winnings + amount)
+	case WalletBucketBonus:
+		b.Bonus = roundCurrency(b.Bonus + amount)
+	}
+}
+
+// Bucket names the single bucket that holds money, or mixed
+func (b WalletBuckets) Bucket() string {
+	bucket := ""
+	for _, name := range []string{WalletBucketDeposit, WalletBucketWinnings, WalletBucketBonus} {
+		if b.get(name) == 0 {
+			continue
+		}
+		if bucket != "" {
+			return WalletBucketMixed
+		}
+		bucket = name
+	}
+	return bucket
+}
+
+// ApplyTo records on a transaction which buckets it moved
+func (b WalletBuckets) ApplyTo(transaction *models.Transaction) {
+	transaction.Bucket = b.Bucket()
+	transaction.DepositAmount = b.Deposit
+	transaction.WinningsAmount = b.Winnings
+	transaction.BonusAmount = b.Bonus
+}
+
+// SingleBucket returns amount held entirely in one bucket
+func SingleBucket(bucket string, amount float64) WalletBuckets {
+	var b WalletBuckets
+	b.add(bucket, amount)
+	return b
+}
+
+// userBucketAccount returns the ledger account backing a user's bucket
+func userBucketAccount(bucket string, userID uuid.UUID) LedgerAccountRef {
+	switch bucket {
+	case WalletBucketWinnings:
+		return UserWinningsAccount(userID)
+	case WalletBucketBonus:
+		return UserBonusAccount(userID)
+	}
+	return UserDepositAccount(userID)
+}
+
+// accountBucket returns the wallet bucket a user account backs, if any
+func accountBucket(accountType string) (string, bool) {
+	switch accountType {
+	case AccountUserDeposit:
+		return WalletBucketDeposit, true
+	case AccountUserWinnings:
+		return WalletBucketWinnings, true
+	case AccountUserBonus:
+		return WalletBucketBonus, true
+	}
+	return "", false
+}
+
+// EntryFeeRules decide which buckets pay an entry fee
+type EntryFeeRules struct {
+	Order           []string // Buckets to draw from, first to last
+	MaxBonusPercent float64  // Cap on the share of a fee paid from bonus
+}
+
+// DefaultEntryFeeRules spend bonus first, up to 10% of the fee, then
+// deposits, and winnings last
+func DefaultEntryFeeRules() EntryFeeRules {
+	return EntryFeeRules{
+		Order:           []string{WalletBucketBonus, WalletBucketDeposit, WalletBucketWinnings},
+		MaxBonusPercent: 10,
+	}
+}
+
+// EntryFeeRulesFromConfig reads the rules from config, falling back to the
+// defaults for anything missing or invalid
+func EntryFeeRulesFromConfig(cfg *config.Config) EntryFeeRules {
+	rules := DefaultEntryFeeRules()
+	if cfg == nil {
+		return rules
+	}
+
+	var order []string
+	for _, bucket := range cfg.EntryFeeBucketOrder {
+		bucket = strings.TrimSpace(strings.ToLower(bucket))
+		switch bucket {
+		case WalletBucketDeposit, WalletBucketWinnings, WalletBucketBonus:
+			order = append(order, bucket)
+		}
+	}
+	if len(order) > 0 {
+		rules.Order = order
+	}
+
+	if cfg.EntryFeeMaxBonusPercent >= 0 && cfg.EntryFeeMaxBonusPercent <= 100 {
+		rules.MaxBonusPercent = cfg.EntryFeeMaxBonusPercent
+	}
+
+	return rules
+}
+
+// Split works out how much of a fee each bucket pays given the available
+// balances, or returns ErrInsufficientFunds if the allowed buckets fall short
+func (r EntryFeeRules) Split(fee float64, available WalletBuckets) (WalletBuckets, error) {
+	split, remaining := r.cover(fee, available)
+	if remaining > 0 {
+		return WalletBuckets{}, fmt.Errorf("%w: ₹%.2f short", ErrInsufficientFunds, remaining)
+	}
+	return split, nil
+}
+
+// Shortfall returns how much the allowed buckets fall short of a fee, which
+// is what a deposit must add before the fee can be paid
+func (r EntryFeeRules) Shortfall(fee float64, available WalletBuckets) float64 {
+	_, remaining := r.cover(fee, available)
+	return remaining
+}
+
+// cover draws fee from the buckets in order, returning what each paid and
+// what is left unpaid
+func (r EntryFeeRules) cover(fee float64, available WalletBuckets) (WalletBuckets, float64) {
+	var split WalletBuckets
+	remaining := roundCurrency(fee)
+
+	for _, bucket := range r.Order {
+		if remaining <= 0 {
+			break
+		}
+
+		take := math.Min(math.Max(available.get(bucket), 0), remaining)
+		if bucket == WalletBucketBonus {
+			take = math.Min(take, math.Floor(fee*r.MaxBonusPercent)/100)
+		}
+		take = roundCurrency(take)
+		if take <= 0 {
+			continue
+		}
+
+		split.add(bucket, take)
+		remaining = roundCurrency(remaining - take)
+	}
+
+	return split, remaining
+}
@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"esports-fantasy-backend/config"
+	"esports-fantasy-backend/internal/models"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Withdrawal statuses
+const (
+	WithdrawalStatusRequested  = "requested"
+	WithdrawalStatusProcessing = "processing"
+	WithdrawalStatusPaid       = "paid"
+	WithdrawalStatusRejected   = "rejected"
+	WithdrawalStatusFailed     = "failed"
+)
+
+const TransactionTypeWithdrawal = "withdrawal"
+
+var (
+	ErrWithdrawalBelowMinimum  = errors.New("withdrawal is below the minimum amount")
+	ErrWithdrawalLimitExceeded = errors.New("daily withdrawal limit exceeded")
+	ErrWithdrawalNotPending    = errors.New("withdrawal is no longer pending")
+	ErrWithdrawalsUnavailable  = errors.New("withdrawals are unavailable")
+)
+
+// payoutTimeout bounds a single call to the payout provider
+const payoutTimeout = 30 * time.Second
+
+type WithdrawalService interface {
+	RequestWithdrawal(userID uuid.UUID, req *models.CreateWithdrawalRequest) (*models.Withdrawal, error)
+	GetUserWithdrawals(userID uuid.UUID) ([]models.Withdrawal, error)
+	GetWithdrawals(status string) ([]models.Withdrawal, error)
+	ApproveWithdrawal(id uuid.UUID, adminID *uuid.UUID) (*models.Withdrawal, error)
+	RejectWithdrawal(id uuid.UUID, adminID *uuid.UUID, reason string) (*models.Withdrawal, error)
+}
+
+type withdrawalService struct {
+	db                  *gorm.DB
+	ledgerService       LedgerService
+	notificationService NotificationService
+	payoutProvider      PayoutProvider
+	config              *config.Config
+}
+
+func NewWithdrawalService(db *gorm.DB, ledgerService LedgerService, notificationService NotificationService, payoutProvider PayoutProvider, config *config.Config) WithdrawalService {
+	return &withdrawalService{
+		db:                  db,
+		ledgerService:       ledgerService,
+		notificationService: notificationService,
+		payoutProvider:      payoutProvider,
+		config:              config,
+	}
+}
+
+// RequestWithdrawal checks the limits and moves the amount from the user's
+// winnings into a hold until an admin reviews the request
+func (s *withdrawalService) RequestWithdrawal(userID uuid.UUID, req *models.CreateWithdrawalRequest) (*models.Withdrawal, error) {
+	if s.payoutProvider == nil {
+		return nil, ErrWithdrawalsUnavailable
+	}
+
+	amount := roundCurrency(req.Amount)
+	if amount < s.config.WithdrawalMinAmount {
+		return nil, fmt.Errorf("%w of ₹%.2f", ErrWithdrawalBelowMinimum, s.config.WithdrawalMinAmount)
+	}
+
+	withdrawal := &models.Withdrawal{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Amount:      amount,
+		Status:      WithdrawalStatusRequested,
+		Method:      req.Method,
+		Destination: req.Destination,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Serialises requests from the same user so limits cannot be raced
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error; err != nil {
+			return fmt.Errorf("user not found")
+		}
+		if user.WinningsBalance < amount {
+			return fmt.Errorf("%w: only ₹%.2f of winnings can be withdrawn", ErrInsufficientFunds, user.WinningsBalance)
+		}
+
+		if err := s.checkDailyLimits(tx, userID, amount); err != nil {
+			return err
+		}
+
+		transaction := &models.Transaction{
+			ID:              uuid.New(),
+			UserID:          userID,
+			Amount:          amount,
+			Type:            TransactionTypeWithdrawal,
+			Status:          "pending",
+			RelatedEntityID: &withdrawal.ID,
+			Description:     "Withdrawal to " + req.Method,
+		}
+		SingleBucket(WalletBucketWinnings, amount).ApplyTo(transaction)
+		if err := tx.Create(transaction).Error; err != nil {
+			return fmt.Errorf("failed to create withdrawal transaction: %w", err)
+		}
+
+		withdrawal.TransactionID = &transaction.ID
+		if err := tx.Create(withdrawal).Error; err != nil {
+			return fmt.Errorf("failed to create withdrawal: %w", err)
+		}
+
+		_, err := s.ledgerService.Post(tx, &JournalEntryRequest{
+			Kind:           JournalKindWithdrawal,
+			ReferenceID:    &withdrawal.ID,
+			TransactionID:  &transaction.ID,
+			IdempotencyKey: fmt.Sprintf("withdrawal_hold:%s", withdrawal.ID),
+			Description:    "Withdrawal hold",
+			Postings: []LedgerPosting{
+				Debit(UserWinningsAccount(userID), amount),
+				Credit(WithdrawalHoldAccount(userID), amount),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to hold withdrawal: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return withdrawal, nil
+}
+
+// checkDailyLimits counts the user's withdrawals since midnight, ignoring
+// those that were rejected or failed
+func (s *withdrawalService) checkDailyLimits(tx *gorm.DB, userID uuid.UUID, amount float64) error {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var today struct {
+		Count int
+		Total float64
+	}
+	err := tx.Model(&models.Withdrawal{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(amount), 0) AS total").
+		Where("user_id = ? AND created_at >= ?", userID, startOfDay).
+		Where("status NOT IN ?", []string{WithdrawalStatusRejected, WithdrawalStatusFailed}).
+		Scan(&today).Error
+	if err != nil {
+		return fmt.Errorf("failed to check withdrawal limits: %w", err)
+	}
+
+	if s.config.WithdrawalDailyCount > 0 && today.Count >= s.config.WithdrawalDailyCount {
+		return fmt.Errorf("%w: at most %d withdrawals per day", ErrWithdrawalLimitExceeded, s.config.WithdrawalDailyCount)
+	}
+	if s.config.WithdrawalDailyLimit > 0 && roundCurrency(today.Total+amount) > s.config.WithdrawalDailyLimit {
+		return fmt.Errorf("%w: ₹%.2f left today", ErrWithdrawalLimitExceeded,
+			roundCurrency(s.config.WithdrawalDailyLimit-today.Total))
+	}
+	return nil
+}
+
+func (s *withdrawalService) GetUserWithdrawals(userID uuid.UUID) ([]models.Withdrawal, error) {
+	var withdrawals []models.Withdrawal
+	err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&withdrawals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withdrawals: %w", err)
+	}
+	return withdrawals, nil
+}
+
+// GetWithdrawals lists withdrawals in a status, oldest first, for the admin
+// review queue
+func (s *withdrawalService) GetWithdrawals(status string) ([]models.Withdrawal, error) {
+	if status == "" {
+		status = WithdrawalStatusRequested
+	}
+
+	var withdrawals []models.Withdrawal
+	err := s.db.Where("status = ?", status).Order("created_at ASC").Find(&withdrawals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withdrawals: %w", err)
+	}
+	return withdrawals, nil
+}
+
+// ApproveWithdrawal sends the payout. A successful payout releases the held
+// money to the provider; a failed one returns it to the user's winnings.
+// Approving a withdrawal left processing by an interrupted payout retries it,
+// which the provider treats as the same payout.
+func (s *withdrawalService) ApproveWithdrawal(id uuid.UUID, adminID *uuid.UUID) (*models.Withdrawal, error) {
+	if s.payoutProvider == nil {
+		return nil, ErrWithdrawalsUnavailable
+	}
+
+	var withdrawal models.Withdrawal
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&withdrawal, "id = ?", id).Error
+		if err != nil {
+			return fmt.Errorf("withdrawal not found")
+		}
+		if withdrawal.Status != WithdrawalStatusRequested && withdrawal.Status != WithdrawalStatusProcessing {
+			return fmt.Errorf("%w: %s", ErrWithdrawalNotPending, withdrawal.Status)
+		}
+
+		now := time.Now()
+		withdrawal.Status = WithdrawalStatusProcessing
+		withdrawal.Provider = s.payoutProvider.Name()
+		withdrawal.ReviewedBy = adminID
+		withdrawal.ReviewedAt = &now
+		return tx.Model(&withdrawal).Updates(map[string]interface{}{
+			"status":      withdrawal.Status,
+			"provider":    withdrawal.Provider,
+			"reviewed_by": adminID,
+			"reviewed_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), payoutTimeout)
+	defer cancel()
+
+	result, payoutErr := s.payoutProvider.Payout(ctx, &PayoutRequest{
+		WithdrawalID: withdrawal.ID,
+		UserID:       withdrawal.UserID,
+		Amount:       withdrawal.Amount,
+		Method:       withdrawal.Method,
+		Destination:  withdrawal.Destination,
+	})
+	if payoutErr != nil {
+		log.Printf("❌ Payout for withdrawal %s failed: %v", withdrawal.ID, payoutErr)
+		return s.closeWithdrawal(id, WithdrawalStatusFailed, map[string]interface{}{
+			"failure_reason": payoutErr.Error(),
+		})
+	}
+
+	return s.closeWithdrawal(id, WithdrawalStatusPaid, map[string]interface{}{
+		"provider_reference": result.Reference,
+		"paid_at":            time.Now(),
+	})
+}
+
+// RejectWithdrawal turns down a pending withdrawal and returns the held money
+// to the user's winnings
+func (s *withdrawalService) RejectWithdrawal(id uuid.UUID, adminID *uuid.UUID, reason string) (*models.Withdrawal, error) {
+	return s.closeWithdrawal(id, WithdrawalStatusRejected, map[string]interface{}{
+		"rejected_reason": reason,
+		"reviewed_by":     adminID,
+		"reviewed_at":     time.Now(),
+	})
+}
+
+// closeWithdrawal moves a withdrawal to a final status and settles its hold:
+// paid withdrawals send it to the gateway, anything else back to winnings
+func (s *withdrawalService) closeWithdrawal(id uuid.UUID, status string, updates map[string]interface{}) (*models.Withdrawal, error) {
+	var withdrawal models.Withdrawal
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&withdrawal, "id = ?", id).Error
+		if err != nil {
+			return fmt.Errorf("withdrawal not found")
+		}
+
+		// Rejection comes from the review queue; paid and failed from a payout
+		expected := WithdrawalStatusProcessing
+		if status == WithdrawalStatusRejected {
+			expected = WithdrawalStatusRequested
+		}
+		if withdrawal.Status != expected {
+			return fmt.Errorf("%w: %s", ErrWithdrawalNotPending, withdrawal.Status)
+		}
+
+		key := fmt.Sprintf("withdrawal_release:%s", withdrawal.ID)
+		description := "Withdrawal released"
+		postings := []LedgerPosting{
+			Debit(WithdrawalHoldAccount(withdrawal.UserID), withdrawal.Amount),
+			Credit(UserWinningsAccount(withdrawal.UserID), withdrawal.Amount),
+		}
+		transactionStatus := "failed"
+		if status == WithdrawalStatusPaid {
+			key = fmt.Sprintf("withdrawal_payout:%s", withdrawal.ID)
+			description = "Withdrawal paid out"
+			postings = []LedgerPosting{
+				Debit(WithdrawalHoldAccount(withdrawal.UserID), withdrawal.Amount),
+				Credit(GatewayClearingAccount(), withdrawal.Amount),
+			}
+			transactionStatus = "completed"
+		}
+
+		_, err = s.ledgerService.Post(tx, &JournalEntryRequest{
+			Kind:           JournalKindWithdrawal,
+			ReferenceID:    &withdrawal.ID,
+			TransactionID:  withdrawal.TransactionID,
+			IdempotencyKey: key,
+			Description:    description,
+			Postings:       postings,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to settle withdrawal hold: %w", err)
+		}
+
+		if withdrawal.TransactionID != nil {
+			err := tx.Model(&models.Transaction{}).Where("id = ?", *withdrawal.TransactionID).
+				Update("status", transactionStatus).Error
+			if err != nil {
+				return fmt.Errorf("failed to update withdrawal transaction: %w", err)
+			}
+		}
+
+		updates["status"] = status
+		if err := tx.Model(&withdrawal).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update withdrawal: %w", err)
+		}
+		return tx.First(&withdrawal, "id = ?", id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyUser(&withdrawal)
+	return &withdrawal, nil
+}
+
+func (s *withdrawalService) notifyUser(withdrawal *models.Withdrawal) {
+	var title, message string
+	switch withdrawal.Status {
+	case WithdrawalStatusPaid:
+		title = "Withdrawal paid"
+		message = fmt.Sprintf("₹%.2f has been sent to %s.", withdrawal.Amount, withdrawal.Destination)
+	case WithdrawalStatusRejected:
+		title = "Withdrawal rejected"
+		message = fmt.Sprintf("Your withdrawal of ₹%.2f was rejected and returned to your winnings: %s", withdrawal.Amount, withdrawal.RejectedReason)
+	case WithdrawalStatusFailed:
+		title = "Withdrawal failed"
+		message = fmt.Sprintf("We could not pay out ₹%.2f, so it has been returned to your winnings.", withdrawal.Amount)
+	default:
+		return
+	}
+
+	data := map[string]interface{}{
+		"withdrawal_id": withdrawal.ID,
+		"amount":        withdrawal.Amount,
+		"status":        withdrawal.Status,
+	}
+	if err := s.notificationService.Notify(withdrawal.UserID, "withdrawal", title, message, data); err != nil {
+		log.Printf("Error notifying user %s of withdrawal: %v", withdrawal.UserID, err)
+	}
+}